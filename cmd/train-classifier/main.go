@@ -0,0 +1,49 @@
+// Command train-classifier exports the typed issues of the given repositories
+// and writes a naive Bayes model the service loads through the classifier
+// "model" setting.
+//
+//	GITHUB_TOKEN=... go run ./cmd/train-classifier -repos syntasso/kratix,syntasso/ske -out model.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/kirederik/ghproject/lib"
+)
+
+func main() {
+	repos := flag.String("repos", "", "comma separated owner/name repositories to train from")
+	limit := flag.Int("limit", 1000, "number of recent issues to scan per repository")
+	out := flag.String("out", "classifier.json", "path to write the model to")
+	flag.Parse()
+
+	if *repos == "" {
+		log.Fatal("-repos is required")
+	}
+
+	ghClient := lib.NewGithubClient()
+	classifier := lib.NewClassifier()
+	for _, repo := range strings.Split(*repos, ",") {
+		owner, name, ok := strings.Cut(strings.TrimSpace(repo), "/")
+		if !ok {
+			log.Fatalf("repository %q is not owner/name", repo)
+		}
+
+		issues, err := ghClient.FetchTypedIssues(owner, name, *limit)
+		if err != nil {
+			log.Fatalf("error exporting issues of %s: %v", repo, err)
+		}
+		for _, issue := range issues {
+			classifier.Train(issue.Type, issue.Title+"\n"+issue.Body)
+		}
+		fmt.Printf("Trained on %d typed issues from %s\n", len(issues), repo)
+	}
+
+	if err := classifier.Save(*out); err != nil {
+		log.Fatal("error saving model: ", err)
+	}
+	fmt.Printf("Model written to %s\n", *out)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/kirederik/ghproject/lib"
+	"github.com/shurcooL/githubv4"
+)
+
+// itemContent is the issue or pull request behind a project item.
+type itemContent struct {
+	Repository string
+	Number     int
+	NodeID     string
+	Title      string
+	Body       string
+	Labels     []string
+}
+
+func (c itemContent) String() string {
+	return fmt.Sprintf("%s#%d", c.Repository, c.Number)
+}
+
+func (c itemContent) typeMapping() *lib.TypeMapping {
+	return currentTypeMapping().ForRepository(c.Repository)
+}
+
+func (c itemContent) isBreakingChange() bool {
+	return c.typeMapping().IsBreakingChange(c.Title, c.Body)
+}
+
+// applyTitleFields sets the project fields derived from an issue or pull
+// request title on its item. Fields that already have the right value are
+// left alone.
+func applyTitleFields(p *project, itemID string, content itemContent) {
+	if p.config.BreakingChangeField == "" && p.config.TypeField == "" && p.config.AreaField == "" {
+		return
+	}
+	item, err := ghClient.FetchProjectItem(itemID)
+	if err != nil {
+		log.Printf("Failed to fetch item %s: %v", itemID, err)
+		return
+	}
+	values := item.Values
+
+	if p.config.BreakingChangeField != "" {
+		if content.isBreakingChange() {
+			setBreakingChangeField(p, itemID, values)
+		} else {
+			clearBreakingChangeField(p, itemID, values)
+		}
+	}
+
+	match, found := content.typeMapping().Match(content.Title)
+	if !found {
+		if p.config.TypeField != "" {
+			clearTypeField(p, itemID, values)
+		}
+		return
+	}
+
+	if p.config.TypeField != "" {
+		setTypeField(p, itemID, match.Type, values)
+	}
+	if p.config.AreaField != "" && match.Scope != "" {
+		setAreaFromScope(p, itemID, match.Scope, values)
+	}
+}
+
+// setTypeField mirrors the detected type into a single select field, so pull
+// requests, which cannot have an issue type, group with issues on the board.
+func setTypeField(p *project, itemID, typeName string, values map[string]string) {
+	field, ok := p.snapshot().FieldsByName[p.config.TypeField].(lib.SingleSelectField)
+	if !ok {
+		log.Printf("Type field %q not found in project %d", p.config.TypeField, p.config.Number)
+		return
+	}
+	option, ok := field.OptionByName(typeName)
+	if !ok {
+		log.Printf("Type field %q has no %q option in project %d", field.Name, typeName, p.config.Number)
+		return
+	}
+	if values[field.Name] == option.Name {
+		return
+	}
+
+	if err := setSingleSelect(p.id, itemID, field, option); err != nil {
+		log.Printf("Failed to set %s to %s: %v", field.Name, option.Name, err)
+		return
+	}
+	fmt.Printf("Set %s to %s\n", field.Name, option.Name)
+}
+
+// clearTypeField unsets the type field once the title no longer has a type
+// prefix, so the item does not keep the type it had before.
+func clearTypeField(p *project, itemID string, values map[string]string) {
+	field, ok := p.snapshot().FieldsByName[p.config.TypeField].(lib.SingleSelectField)
+	if !ok || values[field.Name] == "" {
+		return
+	}
+
+	if err := ghClient.ClearProjectItemField(p.id, itemID, field.ID); err != nil {
+		log.Printf("Failed to clear %s: %v", field.Name, err)
+		return
+	}
+	fmt.Printf("Cleared %s\n", field.Name)
+}
+
+func setBreakingChangeField(p *project, itemID string, values map[string]string) {
+	field, ok := p.snapshot().FieldsByName[p.config.BreakingChangeField].(lib.SingleSelectField)
+	if !ok {
+		log.Printf("Breaking change field %q not found in project %d", p.config.BreakingChangeField, p.config.Number)
+		return
+	}
+	option, ok := field.OptionByName(p.config.BreakingChangeOption)
+	if !ok {
+		log.Printf("Breaking change field %q has no %q option", field.Name, p.config.BreakingChangeOption)
+		return
+	}
+	if values[field.Name] == option.Name {
+		return
+	}
+
+	if err := setSingleSelect(p.id, itemID, field, option); err != nil {
+		log.Printf("Failed to mark item as breaking change: %v", err)
+		return
+	}
+	fmt.Printf("Set %s to %s\n", field.Name, option.Name)
+}
+
+// clearBreakingChangeField unsets the breaking change field once the marker
+// has been removed from the title or body.
+func clearBreakingChangeField(p *project, itemID string, values map[string]string) {
+	field, ok := p.snapshot().FieldsByName[p.config.BreakingChangeField].(lib.SingleSelectField)
+	if !ok || values[field.Name] != p.config.BreakingChangeOption {
+		return
+	}
+
+	if err := ghClient.ClearProjectItemField(p.id, itemID, field.ID); err != nil {
+		log.Printf("Failed to clear breaking change mark: %v", err)
+		return
+	}
+	fmt.Printf("Cleared %s\n", field.Name)
+}
+
+// labelBreakingChange adds the breaking change label to the issue or pull
+// request, or removes it once the marker is gone.
+func labelBreakingChange(content itemContent) {
+	if config.BreakingChangeLabel == "" {
+		return
+	}
+	breaking := content.isBreakingChange()
+	labelled := containsLabel(content.Labels, config.BreakingChangeLabel)
+	if breaking == labelled {
+		return
+	}
+
+	owner, repository, ok := strings.Cut(content.Repository, "/")
+	if !ok {
+		log.Printf("Unexpected repository name %q", content.Repository)
+		return
+	}
+	if !breaking {
+		if err := ghClient.RemoveLabelByName(owner, repository, content.NodeID, config.BreakingChangeLabel); err != nil {
+			log.Printf("Failed to remove %q label: %v", config.BreakingChangeLabel, err)
+			return
+		}
+		fmt.Printf("Removed %s label from %s\n", config.BreakingChangeLabel, content)
+		return
+	}
+	if err := ghClient.AddLabelByName(owner, repository, content.NodeID, config.BreakingChangeLabel); err != nil {
+		log.Printf("Failed to add %q label: %v", config.BreakingChangeLabel, err)
+		return
+	}
+	fmt.Printf("Labelled %s as %s\n", content, config.BreakingChangeLabel)
+}
+
+func containsLabel(labels []string, name string) bool {
+	for _, label := range labels {
+		if strings.EqualFold(label, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func setAreaFromScope(p *project, itemID, scope string, values map[string]string) {
+	field, ok := p.snapshot().FieldsByName[p.config.AreaField].(lib.SingleSelectField)
+	if !ok {
+		log.Printf("Area field %q not found in project %d", p.config.AreaField, p.config.Number)
+		return
+	}
+
+	option, ok := p.config.AreaOption(field, scope)
+	if !ok {
+		log.Printf("Scope %q has no matching %q option in project %d, add it to the field or to scopeAliases", scope, field.Name, p.config.Number)
+		p.recordUnmatchedScope(scope)
+		return
+	}
+	if values[field.Name] == option.Name {
+		return
+	}
+
+	if err := setSingleSelect(p.id, itemID, field, option); err != nil {
+		log.Printf("Failed to set %s to %s: %v", field.Name, option.Name, err)
+		return
+	}
+	fmt.Printf("Set %s to %s from scope %q\n", field.Name, option.Name, scope)
+}
+
+func setSingleSelect(projectID, itemID string, field lib.SingleSelectField, option lib.Field) error {
+	return ghClient.UpdateProjectItem(projectID, itemID, field.ID, githubv4.ProjectV2FieldValue{
+		SingleSelectOptionID: githubv4.NewString(githubv4.String(option.ID)),
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kirederik/ghproject/lib"
+)
+
+// accepts reports whether the project's filter lets subject onto the board.
+func (p *project) accepts(subject lib.FilterSubject) bool {
+	allowed, reason := p.config.Filter.Allows(subject)
+	if !allowed {
+		fmt.Printf("Not adding %s item to project %d: %s\n", subject.Repository, p.config.Number, reason)
+	}
+	return allowed
+}
+
+// acceptsPullRequest reports whether the pull request belongs on the project,
+// applying the bot configuration before the project's filter.
+func acceptsPullRequest(p *project, event EventPayload, subject lib.FilterSubject) bool {
+	if isBotPullRequest(event) && !config.Bots.AllowsProject(p.config.Number) {
+		fmt.Printf("Not adding %s PR to project %d: opened by %s\n", event.Repository.FullName, p.config.Number, event.PullRequest.User.Name)
+		return false
+	}
+	return p.accepts(subject)
+}
+
+func isBotPullRequest(event EventPayload) bool {
+	return config.Bots.IsBot(event.PullRequest.User.Name, event.PullRequest.User.Type)
+}
+
+func issueSubject(event EventPayload) lib.FilterSubject {
+	subject := lib.FilterSubject{
+		Repository: event.Repository.FullName,
+		Author:     event.Issue.User.Name,
+		Labels:     labelNames(event.Issue.Labels),
+	}
+	if event.Issue.Type != nil {
+		subject.IssueType = event.Issue.Type.Name
+	} else {
+		subject.IssueType, _ = currentTypeMapping().ForRepository(subject.Repository).GetTypeFromTitle(event.Issue.Title)
+	}
+	return subject
+}
+
+// closingIssueSubject describes an issue linked to a pull request, which may
+// be in another repository.
+func closingIssueSubject(issue lib.ClosingIssue) lib.FilterSubject {
+	subject := lib.FilterSubject{
+		Repository: issue.Repository,
+		Author:     issue.Author,
+		Labels:     issue.Labels,
+		IssueType:  issue.IssueType,
+	}
+	if subject.IssueType == "" {
+		subject.IssueType, _ = currentTypeMapping().ForRepository(subject.Repository).GetTypeFromTitle(issue.Title)
+	}
+	return subject
+}
+
+// pullRequestSubject describes a pull request by the type its title selects,
+// as pull requests have no issue type of their own.
+func pullRequestSubject(event EventPayload) lib.FilterSubject {
+	subject := lib.FilterSubject{
+		Repository: event.Repository.FullName,
+		Author:     event.PullRequest.User.Name,
+		Labels:     labelNames(event.PullRequest.Labels),
+	}
+	subject.IssueType, _ = currentTypeMapping().ForRepository(subject.Repository).GetTypeFromTitle(event.PullRequest.Title)
+	return subject
+}
+
+func labelNames(labels []Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
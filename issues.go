@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kirederik/ghproject/lib"
+)
+
+// closeIssue moves a closed issue to the status for the reason it was closed.
+func closeIssue(event EventPayload) {
+	if event.Issue.StateReason == "not_planned" {
+		moveIssue(event, func(statuses lib.IssueStatuses) string { return statuses.NotPlanned })
+	} else {
+		moveIssue(event, func(statuses lib.IssueStatuses) string { return statuses.Completed })
+	}
+}
+
+// moveIssue moves the issue to the status pick selects in each project.
+// Reopening is a backward move, so the usual transition policy clears End
+// date and counts the reopening.
+func moveIssue(event EventPayload, pick func(lib.IssueStatuses) string) {
+	subject := issueSubject(event)
+	for _, p := range projects {
+		if !p.accepts(subject) {
+			continue
+		}
+		if status := pick(p.config.IssueStatuses); status != "" {
+			moveContentToStatus(p, event.Issue.NodeID, status, false)
+		}
+	}
+}
+
+// removeIssueItems archives or deletes the items of an issue that was
+// transferred or deleted, which would otherwise stay on the board.
+func removeIssueItems(event EventPayload, remove func(projectID, itemID string) error) {
+	for _, p := range projects {
+		// A deleted issue can no longer be looked up, only found in the cache.
+		itemID, err := p.findItem(event.Issue.NodeID)
+		if err != nil {
+			log.Printf("Failed to find issue %s#%d in project %d: %v", event.Repository.FullName, event.Issue.Number, p.config.Number, err)
+			continue
+		}
+		if itemID == "" {
+			continue
+		}
+		if err := remove(p.id, itemID); err != nil {
+			log.Printf("Failed to remove item %s of %s issue from project %d: %v", itemID, event.Action, p.config.Number, err)
+			continue
+		}
+		p.members.Remove(itemID)
+		fmt.Printf("Removed %s issue %s#%d from project %d\n", event.Action, event.Repository.FullName, event.Issue.Number, p.config.Number)
+	}
+}
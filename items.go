@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// handleItemAdded treats an issue or pull request added in the project UI,
+// or a draft converted to an issue, the same as one added from its own
+// webhook.
+func handleItemAdded(p *project, event EventPayload) {
+	item := event.ProjectV2Item
+	if item.ContentNodeID == "" || item.ContentType == "DraftIssue" {
+		return
+	}
+	if event.Action == "created" && p.members.AddedByAutomation(item.ContentNodeID, item.NodeID) {
+		fmt.Printf("Item %s was added by the automation\n", item.NodeID)
+		return
+	}
+	p.members.Add(item.ContentNodeID, item.NodeID)
+
+	found, err := ghClient.FetchItemContent(item.ContentNodeID)
+	if err != nil {
+		log.Printf("Failed to fetch the content of item %s: %v", item.NodeID, err)
+		return
+	}
+	if found == nil {
+		return
+	}
+	fmt.Printf("%s %s in %s was %s on project %d\n", found.Kind, item.ContentNodeID, found.Repository, event.Action, p.config.Number)
+
+	content := itemContent{
+		Repository: found.Repository,
+		Number:     found.Number,
+		NodeID:     item.ContentNodeID,
+		Title:      found.Title,
+		Body:       found.Body,
+		Labels:     found.Labels,
+	}
+	applyTitleFields(p, item.NodeID, content)
+	labelBreakingChange(content)
+	if found.Kind == "Issue" {
+		assignTypeToIssue(content.Repository, content.Title, "", content.NodeID)
+	}
+}
@@ -0,0 +1,85 @@
+package lib
+
+import (
+	"sync"
+	"time"
+)
+
+// ArchiveLog tracks which project items are archived, loaded from the board
+// and kept current from archive events, and counts those events since the
+// process started.
+type ArchiveLog struct {
+	mu    sync.Mutex
+	items map[string]bool
+	// changed is when each item was last archived, restored or deleted, so
+	// a listing that started earlier does not undo the change.
+	changed  map[string]time.Time
+	archived int
+	restored int
+}
+
+func NewArchiveLog() *ArchiveLog {
+	return &ArchiveLog{items: make(map[string]bool), changed: make(map[string]time.Time)}
+}
+
+// Replace swaps the archived items for a listing of the board that started at
+// listedAt, keeping the changes made since.
+func (a *ArchiveLog) Replace(itemIDs []string, listedAt time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	items := make(map[string]bool, len(itemIDs))
+	for _, itemID := range itemIDs {
+		items[itemID] = true
+	}
+	for itemID, changedAt := range a.changed {
+		if !changedAt.After(listedAt) {
+			delete(a.changed, itemID)
+			continue
+		}
+		if a.items[itemID] {
+			items[itemID] = true
+		} else {
+			delete(items, itemID)
+		}
+	}
+	a.items = items
+}
+
+func (a *ArchiveLog) Archive(itemID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.items[itemID] = true
+	a.changed[itemID] = time.Now()
+	a.archived++
+}
+
+func (a *ArchiveLog) Restore(itemID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.items, itemID)
+	a.changed[itemID] = time.Now()
+	a.restored++
+}
+
+// Forget drops a deleted item without counting an event.
+func (a *ArchiveLog) Forget(itemID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.items, itemID)
+	a.changed[itemID] = time.Now()
+}
+
+// Len returns how many items are archived.
+func (a *ArchiveLog) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.items)
+}
+
+// Events returns how many archive and restore events were seen since the
+// process started.
+func (a *ArchiveLog) Events() (archived, restored int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.archived, a.restored
+}
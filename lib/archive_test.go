@@ -0,0 +1,47 @@
+package lib
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ArchiveLog", func() {
+	var archive *ArchiveLog
+
+	BeforeEach(func() {
+		archive = NewArchiveLog()
+		archive.Replace([]string{"item-1", "item-2"}, time.Now())
+	})
+
+	It("should report the items archived before it started", func() {
+		Expect(archive.Len()).To(Equal(2))
+		archived, restored := archive.Events()
+		Expect(archived).To(BeZero())
+		Expect(restored).To(BeZero())
+	})
+
+	It("should follow archived, restored and deleted items", func() {
+		archive.Archive("item-3")
+		archive.Restore("item-1")
+		archive.Forget("item-2")
+
+		Expect(archive.Len()).To(Equal(1))
+		archived, restored := archive.Events()
+		Expect(archived).To(Equal(1))
+		Expect(restored).To(Equal(1), "deleting an item is not a restore")
+	})
+
+	It("should keep events that arrive while the listing is running", func() {
+		listedAt := time.Now().Add(-time.Second)
+		archive.Archive("item-3")
+		archive.Restore("item-1")
+
+		archive.Replace([]string{"item-1", "item-2"}, listedAt)
+
+		Expect(archive.Len()).To(Equal(2))
+		archive.Replace([]string{"item-1"}, time.Now())
+		Expect(archive.Len()).To(Equal(1))
+	})
+})
@@ -0,0 +1,28 @@
+package lib
+
+import "strings"
+
+// AreaOption returns the option of the area field selected by a title scope.
+// The scope is first translated through ScopeAliases and then compared with
+// the option names, ignoring case.
+func (p *ProjectConfig) AreaOption(field SingleSelectField, scope string) (Field, bool) {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	if scope == "" {
+		return Field{}, false
+	}
+
+	name := scope
+	for alias, option := range p.ScopeAliases {
+		if strings.ToLower(alias) == scope {
+			name = option
+			break
+		}
+	}
+
+	for _, option := range field.Options {
+		if strings.EqualFold(option.Name, name) {
+			return option, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,50 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AreaOption", func() {
+	var (
+		config ProjectConfig
+		field  SingleSelectField
+	)
+
+	BeforeEach(func() {
+		config = DefaultProjectConfig(1)
+		config.AreaField = "Area"
+		config.ScopeAliases = map[string]string{
+			"ske-operator": "SKE",
+			"UI":           "Frontend",
+		}
+		field = SingleSelectField{ID: "area", Name: "Area", Options: map[string]Field{
+			"o1": {ID: "o1", Name: "SKE"},
+			"o2": {ID: "o2", Name: "Frontend"},
+			"o3": {ID: "o3", Name: "Docs"},
+		}}
+	})
+
+	It("should match option names ignoring case", func() {
+		option, found := config.AreaOption(field, "docs")
+		Expect(found).To(BeTrue())
+		Expect(option.ID).To(Equal("o3"))
+	})
+
+	It("should translate scopes through the aliases", func() {
+		option, found := config.AreaOption(field, "ske-operator")
+		Expect(found).To(BeTrue())
+		Expect(option.ID).To(Equal("o1"))
+
+		option, found = config.AreaOption(field, "ui")
+		Expect(found).To(BeTrue())
+		Expect(option.ID).To(Equal("o2"))
+	})
+
+	It("should not match unknown or empty scopes", func() {
+		_, found := config.AreaOption(field, "billing")
+		Expect(found).To(BeFalse())
+		_, found = config.AreaOption(field, " ")
+		Expect(found).To(BeFalse())
+	})
+})
@@ -0,0 +1,278 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+const DefaultBatchSize = 25
+
+// BatchOperation is a single mutation that a MutationBatcher can combine with
+// others into one aliased GraphQL document.
+type BatchOperation interface {
+	mutation() string
+	inputType() string
+	selection() string
+	input() interface{}
+	itemID(data json.RawMessage) string
+}
+
+type UpdateFieldOperation struct {
+	ProjectID string
+	ItemID    string
+	FieldID   string
+	Value     githubv4.ProjectV2FieldValue
+}
+
+func (o UpdateFieldOperation) mutation() string  { return "updateProjectV2ItemFieldValue" }
+func (o UpdateFieldOperation) inputType() string { return "UpdateProjectV2ItemFieldValueInput!" }
+func (o UpdateFieldOperation) selection() string { return "projectV2Item { id }" }
+
+func (o UpdateFieldOperation) input() interface{} {
+	return githubv4.UpdateProjectV2ItemFieldValueInput{
+		ProjectID: githubv4.ID(o.ProjectID),
+		ItemID:    githubv4.ID(o.ItemID),
+		FieldID:   githubv4.ID(o.FieldID),
+		Value:     o.Value,
+	}
+}
+
+func (o UpdateFieldOperation) itemID(data json.RawMessage) string {
+	var result struct {
+		ProjectV2Item struct {
+			ID string `json:"id"`
+		} `json:"projectV2Item"`
+	}
+	json.Unmarshal(data, &result)
+	return result.ProjectV2Item.ID
+}
+
+type AddItemOperation struct {
+	ProjectID string
+	ContentID string
+}
+
+func (o AddItemOperation) mutation() string  { return "addProjectV2ItemById" }
+func (o AddItemOperation) inputType() string { return "AddProjectV2ItemByIdInput!" }
+func (o AddItemOperation) selection() string { return "item { id }" }
+
+func (o AddItemOperation) input() interface{} {
+	return githubv4.AddProjectV2ItemByIdInput{
+		ProjectID: githubv4.ID(o.ProjectID),
+		ContentID: githubv4.ID(o.ContentID),
+	}
+}
+
+func (o AddItemOperation) itemID(data json.RawMessage) string {
+	var result struct {
+		Item struct {
+			ID string `json:"id"`
+		} `json:"item"`
+	}
+	json.Unmarshal(data, &result)
+	return result.Item.ID
+}
+
+// BatchResult is the outcome of one queued operation. ItemID is the project
+// item the mutation returned, when it succeeded.
+type BatchResult struct {
+	Operation BatchOperation
+	ItemID    string
+	Err       error
+}
+
+// MutationBatcher queues mutations and sends them as aliased GraphQL documents
+// of at most size operations each.
+type MutationBatcher struct {
+	client     *GithubClient
+	size       int
+	operations []BatchOperation
+}
+
+func (g *GithubClient) NewMutationBatcher(size int) *MutationBatcher {
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	return &MutationBatcher{
+		client: g,
+		size:   size,
+	}
+}
+
+func (b *MutationBatcher) Add(op BatchOperation) {
+	b.operations = append(b.operations, op)
+}
+
+func (b *MutationBatcher) Len() int {
+	return len(b.operations)
+}
+
+// Flush sends every queued operation and empties the queue. A result is
+// returned for each operation in the order they were added; the error joins
+// the errors of every failed operation.
+func (b *MutationBatcher) Flush() ([]BatchResult, error) {
+	operations := b.operations
+	b.operations = nil
+
+	var results []BatchResult
+	for start := 0; start < len(operations); start += b.size {
+		end := min(start+b.size, len(operations))
+		results = append(results, b.send(operations[start:end])...)
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+func (b *MutationBatcher) send(operations []BatchOperation) []BatchResult {
+	results := make([]BatchResult, len(operations))
+	for i, op := range operations {
+		results[i].Operation = op
+	}
+
+	recordedAt := make([]time.Time, len(operations))
+	for i, op := range operations {
+		if update, ok := op.(UpdateFieldOperation); ok {
+			recordedAt[i] = b.client.recordWrite(update.ItemID, update.FieldID)
+		}
+	}
+	// Writes that failed will not come back as events.
+	defer func() {
+		for i, op := range operations {
+			if update, ok := op.(UpdateFieldOperation); ok && results[i].Err != nil {
+				b.client.forgetWrite(update.ItemID, update.FieldID, recordedAt[i])
+			}
+		}
+	}()
+
+	document, variables := buildBatchDocument(operations)
+	response, err := b.client.doGraphQL(document, variables)
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	for _, gqlErr := range response.Errors {
+		index, ok := batchAliasIndex(gqlErr.Path)
+		if !ok || index >= len(results) {
+			// An error that is not tied to an alias fails the whole document.
+			for i := range results {
+				results[i].Err = errors.Join(results[i].Err, gqlErr)
+			}
+			continue
+		}
+		results[index].Err = errors.Join(results[index].Err, gqlErr)
+	}
+
+	for i, op := range operations {
+		data, ok := response.Data[batchAlias(i)]
+		if !ok || string(data) == "null" {
+			if results[i].Err == nil {
+				results[i].Err = fmt.Errorf("%s returned no data", op.mutation())
+			}
+			continue
+		}
+		results[i].ItemID = op.itemID(data)
+	}
+	return results
+}
+
+func batchAlias(index int) string {
+	return fmt.Sprintf("op%d", index)
+}
+
+func batchAliasIndex(path []interface{}) (int, bool) {
+	if len(path) == 0 {
+		return 0, false
+	}
+	alias, ok := path[0].(string)
+	if !ok {
+		return 0, false
+	}
+	var index int
+	if _, err := fmt.Sscanf(alias, "op%d", &index); err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
+func buildBatchDocument(operations []BatchOperation) (string, map[string]interface{}) {
+	var params, fields []string
+	variables := make(map[string]interface{}, len(operations))
+	for i, op := range operations {
+		variable := fmt.Sprintf("input%d", i)
+		params = append(params, fmt.Sprintf("$%s: %s", variable, op.inputType()))
+		fields = append(fields, fmt.Sprintf("%s: %s(input: $%s) { %s }", batchAlias(i), op.mutation(), variable, op.selection()))
+		variables[variable] = op.input()
+	}
+	document := fmt.Sprintf("mutation(%s) { %s }", strings.Join(params, ", "), strings.Join(fields, " "))
+	return document, variables
+}
+
+type graphQLError struct {
+	Message string        `json:"message"`
+	Type    string        `json:"type"`
+	Path    []interface{} `json:"path"`
+}
+
+func (e graphQLError) Error() string {
+	if len(e.Path) > 0 {
+		return fmt.Sprintf("%v: %s", e.Path[0], e.Message)
+	}
+	return e.Message
+}
+
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []graphQLError             `json:"errors"`
+}
+
+// doGraphQL sends a raw document for the cases the struct based client cannot
+// express, such as a dynamic number of aliased mutations.
+func (g *GithubClient) doGraphQL(document string, variables map[string]interface{}) (*graphQLResponse, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     document,
+		"variables": variables,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(g.ctx, http.MethodPost, g.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("non-200 OK status code: %v body: %q", resp.Status, respBody)
+	}
+
+	var response graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,123 @@
+package lib
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/shurcooL/githubv4"
+)
+
+var _ = Describe("MutationBatcher", func() {
+	var (
+		server    *httptest.Server
+		documents []string
+		respond   func(document string) string
+		client    *GithubClient
+		batcher   *MutationBatcher
+	)
+
+	BeforeEach(func() {
+		documents = nil
+		respond = func(document string) string {
+			return `{"data": {}}`
+		}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				Query string `json:"query"`
+			}
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+			documents = append(documents, request.Query)
+			w.Write([]byte(respond(request.Query)))
+		}))
+		client = newGithubClient(server.Client(), server.URL)
+		batcher = client.NewMutationBatcher(2)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should combine operations into aliased documents split by size", func() {
+		respond = func(document string) string {
+			if strings.Contains(document, "addProjectV2ItemById") {
+				return `{"data": {"op0": {"item": {"id": "item-3"}}}}`
+			}
+			return `{"data": {"op0": {"projectV2Item": {"id": "item-1"}}, "op1": {"projectV2Item": {"id": "item-2"}}}}`
+		}
+
+		batcher.Add(UpdateFieldOperation{ProjectID: "p", ItemID: "item-1", FieldID: "f", Value: githubv4.ProjectV2FieldValue{Text: githubv4.NewString("a")}})
+		batcher.Add(UpdateFieldOperation{ProjectID: "p", ItemID: "item-2", FieldID: "f", Value: githubv4.ProjectV2FieldValue{Text: githubv4.NewString("b")}})
+		batcher.Add(AddItemOperation{ProjectID: "p", ContentID: "issue-1"})
+
+		results, err := batcher.Flush()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(documents).To(HaveLen(2))
+		Expect(documents[0]).To(ContainSubstring("op0: updateProjectV2ItemFieldValue(input: $input0)"))
+		Expect(documents[0]).To(ContainSubstring("op1: updateProjectV2ItemFieldValue(input: $input1)"))
+		Expect(documents[1]).To(ContainSubstring("op0: addProjectV2ItemById(input: $input0)"))
+
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].ItemID).To(Equal("item-1"))
+		Expect(results[1].ItemID).To(Equal("item-2"))
+		Expect(results[2].ItemID).To(Equal("item-3"))
+		Expect(batcher.Len()).To(BeZero())
+	})
+
+	It("should record field writes in the loop guard", func() {
+		respond = func(document string) string {
+			return `{"data": {"op0": {"projectV2Item": {"id": "item-1"}}, "op1": null},
+				"errors": [{"message": "field not found", "path": ["op1"]}]}`
+		}
+		guard := NewLoopGuard(time.Minute)
+		client.TrackWrites(guard)
+
+		batcher.Add(UpdateFieldOperation{ProjectID: "p", ItemID: "item-1", FieldID: "f"})
+		batcher.Add(UpdateFieldOperation{ProjectID: "p", ItemID: "item-2", FieldID: "missing"})
+		batcher.Flush()
+
+		Expect(guard.IsEcho("item-1", "f", time.Now())).To(BeTrue())
+		Expect(guard.IsEcho("item-2", "missing", time.Now())).To(BeFalse(), "failed writes do not come back")
+	})
+
+	It("should map alias errors back to the originating operation", func() {
+		respond = func(document string) string {
+			return `{"data": {"op0": {"projectV2Item": {"id": "item-1"}}, "op1": null},
+				"errors": [{"message": "field not found", "path": ["op1"]}]}`
+		}
+
+		batcher.Add(UpdateFieldOperation{ProjectID: "p", ItemID: "item-1", FieldID: "f"})
+		batcher.Add(UpdateFieldOperation{ProjectID: "p", ItemID: "item-2", FieldID: "missing"})
+
+		results, err := batcher.Flush()
+		Expect(err).To(MatchError(ContainSubstring("field not found")))
+		Expect(results[0].Err).NotTo(HaveOccurred())
+		Expect(results[1].Err).To(MatchError(ContainSubstring("field not found")))
+		Expect(results[1].Operation.(UpdateFieldOperation).FieldID).To(Equal("missing"))
+	})
+
+	It("should fail every operation in a document when the error has no alias", func() {
+		respond = func(document string) string {
+			return `{"errors": [{"message": "rate limited"}]}`
+		}
+
+		batcher.Add(AddItemOperation{ProjectID: "p", ContentID: "issue-1"})
+		batcher.Add(AddItemOperation{ProjectID: "p", ContentID: "issue-2"})
+
+		results, err := batcher.Flush()
+		Expect(err).To(HaveOccurred())
+		Expect(results[0].Err).To(MatchError(ContainSubstring("rate limited")))
+		Expect(results[1].Err).To(MatchError(ContainSubstring("rate limited")))
+	})
+
+	It("should not send anything when the queue is empty", func() {
+		results, err := batcher.Flush()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeEmpty())
+		Expect(documents).To(BeEmpty())
+	})
+})
@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// BotInclude treats bot pull requests like any other, except that the
+	// bot is never assigned.
+	BotInclude = "include"
+	// BotSkip leaves bot pull requests off every board.
+	BotSkip = "skip"
+	// BotRoute only adds bot pull requests to BotConfig.Projects, moved to
+	// BotConfig.Status when set.
+	BotRoute = "route"
+)
+
+// BotConfig decides what happens to pull requests opened by bots such as
+// dependabot and renovate.
+type BotConfig struct {
+	Mode string `json:"mode"`
+	// Logins lists accounts handled as bots besides GitHub App accounts,
+	// which are recognised by their "[bot]" suffix.
+	Logins   []string `json:"logins"`
+	Projects []int    `json:"projects"`
+	Status   string   `json:"status"`
+	// Assignee is the on-duty owner assigned to bot pull requests.
+	Assignee string `json:"assignee"`
+}
+
+// IsBot reports whether login, whose account type is userType, is a bot.
+func (b BotConfig) IsBot(login, userType string) bool {
+	return userType == "Bot" || strings.HasSuffix(login, "[bot]") || containsFold(b.Logins, login)
+}
+
+// AllowsProject reports whether bot pull requests go to the project.
+func (b BotConfig) AllowsProject(number int) bool {
+	switch b.Mode {
+	case BotSkip:
+		return false
+	case BotRoute:
+		return slices.Contains(b.Projects, number)
+	}
+	return true
+}
+
+func (b BotConfig) validate() error {
+	switch b.Mode {
+	case BotInclude, BotSkip:
+	case BotRoute:
+		if len(b.Projects) == 0 {
+			return fmt.Errorf("bot mode %q needs projects", b.Mode)
+		}
+	default:
+		return fmt.Errorf("unknown bot mode %q", b.Mode)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BotConfig", func() {
+	It("should recognise app accounts and configured logins", func() {
+		bots := BotConfig{Logins: []string{"kratix-ci"}}
+		Expect(bots.IsBot("dependabot[bot]", "Bot")).To(BeTrue())
+		Expect(bots.IsBot("renovate[bot]", "")).To(BeTrue())
+		Expect(bots.IsBot("Kratix-CI", "User")).To(BeTrue())
+		Expect(bots.IsBot("derik", "User")).To(BeFalse())
+	})
+
+	It("should pick the projects bot pull requests go to", func() {
+		Expect(BotConfig{Mode: BotInclude}.AllowsProject(4)).To(BeTrue())
+		Expect(BotConfig{Mode: BotSkip}.AllowsProject(4)).To(BeFalse())
+
+		route := BotConfig{Mode: BotRoute, Projects: []int{7}}
+		Expect(route.AllowsProject(7)).To(BeTrue())
+		Expect(route.AllowsProject(4)).To(BeFalse())
+	})
+
+	It("should validate the mode", func() {
+		Expect(BotConfig{Mode: BotInclude}.validate()).To(Succeed())
+		Expect(BotConfig{Mode: BotRoute}.validate()).To(MatchError(ContainSubstring("needs projects")))
+		Expect(BotConfig{Mode: "ignore"}.validate()).To(MatchError(ContainSubstring("unknown bot mode")))
+	})
+})
@@ -0,0 +1,119 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type CalendarConfig struct {
+	Timezone string `json:"timezone"`
+	// WorkingDays lists weekday names such as "Monday". When empty every day
+	// is a working day.
+	WorkingDays []string `json:"workingDays"`
+	// Holidays lists non-working dates as YYYY-MM-DD.
+	Holidays []string `json:"holidays"`
+	// RollToWorkingDay stamps dates that fall on a non-working day with the
+	// next working day instead.
+	RollToWorkingDay bool `json:"rollToWorkingDay"`
+}
+
+// DurationMapping writes the number of working days between the Start and End
+// date fields into the number field Field once End is stamped.
+type DurationMapping struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Field string `json:"field"`
+}
+
+type Calendar struct {
+	location         *time.Location
+	workingDays      map[time.Weekday]bool
+	holidays         map[string]bool
+	rollToWorkingDay bool
+}
+
+func NewCalendar(config CalendarConfig) (*Calendar, error) {
+	location, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading timezone %q: %w", config.Timezone, err)
+	}
+
+	calendar := &Calendar{
+		location:         location,
+		holidays:         make(map[string]bool),
+		rollToWorkingDay: config.RollToWorkingDay,
+	}
+
+	if len(config.WorkingDays) > 0 {
+		calendar.workingDays = make(map[time.Weekday]bool)
+		for _, name := range config.WorkingDays {
+			weekday, ok := parseWeekday(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown working day %q", name)
+			}
+			calendar.workingDays[weekday] = true
+		}
+	}
+
+	for _, holiday := range config.Holidays {
+		if _, err := time.Parse(dateLayout, holiday); err != nil {
+			return nil, fmt.Errorf("holiday %q is not a YYYY-MM-DD date", holiday)
+		}
+		calendar.holidays[holiday] = true
+	}
+	return calendar, nil
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// Today returns the calendar date of now in the calendar's timezone as
+// midnight UTC, which GitHub stores without shifting it to another day.
+func (c *Calendar) Today(now time.Time) time.Time {
+	local := now.In(c.location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if c.rollToWorkingDay {
+		for !c.IsWorkingDay(day) {
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+	return day
+}
+
+func (c *Calendar) IsWorkingDay(day time.Time) bool {
+	if c.holidays[day.Format(dateLayout)] {
+		return false
+	}
+	return c.workingDays == nil || c.workingDays[day.Weekday()]
+}
+
+// WorkingDaysBetween counts the working days after start up to and including
+// end, so an item started and finished on the same day took zero days.
+func (c *Calendar) WorkingDaysBetween(start, end time.Time) int {
+	days := 0
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(day) {
+			days++
+		}
+	}
+	return days
+}
+
+// ParseDate parses a project date field value.
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse(dateLayout, value)
+}
+
+// FormatDate formats day the way project date fields store it.
+func FormatDate(day time.Time) string {
+	return day.Format(dateLayout)
+}
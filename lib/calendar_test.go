@@ -0,0 +1,69 @@
+package lib
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Calendar", func() {
+	date := func(value string) time.Time {
+		day, err := ParseDate(value)
+		Expect(err).NotTo(HaveOccurred())
+		return day
+	}
+
+	Describe("NewCalendar", func() {
+		It("should reject unknown timezones, weekdays and holidays", func() {
+			_, err := NewCalendar(CalendarConfig{Timezone: "Mars/Olympus"})
+			Expect(err).To(HaveOccurred())
+			_, err = NewCalendar(CalendarConfig{WorkingDays: []string{"Funday"}})
+			Expect(err).To(HaveOccurred())
+			_, err = NewCalendar(CalendarConfig{Holidays: []string{"25/12/2024"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Today", func() {
+		It("should use the calendar date in the configured timezone", func() {
+			calendar, err := NewCalendar(CalendarConfig{Timezone: "America/Los_Angeles"})
+			Expect(err).NotTo(HaveOccurred())
+
+			evening := time.Date(2024, 5, 2, 3, 0, 0, 0, time.UTC)
+			Expect(FormatDate(calendar.Today(evening))).To(Equal("2024-05-01"))
+			Expect(calendar.Today(evening).Location()).To(Equal(time.UTC))
+		})
+
+		It("should roll forward to the next working day when configured", func() {
+			calendar, err := NewCalendar(CalendarConfig{
+				WorkingDays:      []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+				Holidays:         []string{"2024-05-06"},
+				RollToWorkingDay: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			saturday := time.Date(2024, 5, 4, 12, 0, 0, 0, time.UTC)
+			Expect(FormatDate(calendar.Today(saturday))).To(Equal("2024-05-07"))
+		})
+	})
+
+	Describe("WorkingDaysBetween", func() {
+		It("should count calendar days without working days configured", func() {
+			calendar, err := NewCalendar(CalendarConfig{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calendar.WorkingDaysBetween(date("2024-05-01"), date("2024-05-08"))).To(Equal(7))
+			Expect(calendar.WorkingDaysBetween(date("2024-05-01"), date("2024-05-01"))).To(BeZero())
+		})
+
+		It("should skip weekends and holidays", func() {
+			calendar, err := NewCalendar(CalendarConfig{
+				WorkingDays: []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+				Holidays:    []string{"2024-05-06"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			// Friday to the following Wednesday, with the Monday off.
+			Expect(calendar.WorkingDaysBetween(date("2024-05-03"), date("2024-05-08"))).To(Equal(2))
+		})
+	})
+})
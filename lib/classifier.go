@@ -0,0 +1,123 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"unicode"
+)
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "are": true, "was": true, "when": true,
+	"not": true, "but": true, "have": true, "has": true, "can": true,
+	"should": true, "would": true, "into": true, "our": true, "you": true,
+}
+
+// Classifier is a multinomial naive Bayes model that suggests an issue type
+// from the words in an issue's title and body. It is trained offline from
+// issues that already have a type and saved as JSON.
+type Classifier struct {
+	DocCounts  map[string]int            `json:"docCounts"`
+	WordCounts map[string]map[string]int `json:"wordCounts"`
+	TotalWords map[string]int            `json:"totalWords"`
+	Vocabulary map[string]bool           `json:"vocabulary"`
+}
+
+func NewClassifier() *Classifier {
+	return &Classifier{
+		DocCounts:  make(map[string]int),
+		WordCounts: make(map[string]map[string]int),
+		TotalWords: make(map[string]int),
+		Vocabulary: make(map[string]bool),
+	}
+}
+
+func LoadClassifier(path string) (*Classifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	classifier := NewClassifier()
+	if err := json.Unmarshal(data, classifier); err != nil {
+		return nil, err
+	}
+	return classifier, nil
+}
+
+func (c *Classifier) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (c *Classifier) Train(typeName, text string) {
+	c.DocCounts[typeName]++
+	if c.WordCounts[typeName] == nil {
+		c.WordCounts[typeName] = make(map[string]int)
+	}
+	for _, word := range tokenize(text) {
+		c.WordCounts[typeName][word]++
+		c.TotalWords[typeName]++
+		c.Vocabulary[word] = true
+	}
+}
+
+// Classify returns the most likely type for text and its posterior
+// probability. It returns an empty type when the model has not been trained
+// or text has no known words.
+func (c *Classifier) Classify(text string) (string, float64) {
+	var words []string
+	for _, word := range tokenize(text) {
+		if c.Vocabulary[word] {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return "", 0
+	}
+
+	totalDocs := 0
+	for _, count := range c.DocCounts {
+		totalDocs += count
+	}
+
+	scores := make(map[string]float64, len(c.DocCounts))
+	best, bestScore := "", math.Inf(-1)
+	for typeName, docCount := range c.DocCounts {
+		score := math.Log(float64(docCount) / float64(totalDocs))
+		denominator := float64(c.TotalWords[typeName] + len(c.Vocabulary))
+		for _, word := range words {
+			score += math.Log(float64(c.WordCounts[typeName][word]+1) / denominator)
+		}
+		scores[typeName] = score
+		if score > bestScore || (score == bestScore && typeName < best) {
+			best, bestScore = typeName, score
+		}
+	}
+
+	// Normalise the log scores into a probability for the best type.
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - bestScore)
+	}
+	return best, 1 / sum
+}
+
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := fields[:0]
+	for _, word := range fields {
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
@@ -0,0 +1,58 @@
+package lib
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Classifier", func() {
+	var classifier *Classifier
+
+	BeforeEach(func() {
+		classifier = NewClassifier()
+		classifier.Train("Bug", "crash when saving the pipeline, panic in controller")
+		classifier.Train("Bug", "error message shown on login, crash after upgrade")
+		classifier.Train("Bug", "promise fails with nil pointer panic")
+		classifier.Train("Feature", "support multiple destinations for a promise")
+		classifier.Train("Feature", "add option to configure pipeline timeout")
+		classifier.Train("Docs", "document how to write a promise, update quick start guide")
+	})
+
+	It("should suggest the most likely type with its confidence", func() {
+		typeName, confidence := classifier.Classify("panic and crash in the controller")
+		Expect(typeName).To(Equal("Bug"))
+		Expect(confidence).To(BeNumerically(">", 0.8))
+
+		typeName, _ = classifier.Classify("add support for configuring destinations")
+		Expect(typeName).To(Equal("Feature"))
+	})
+
+	It("should be less confident about ambiguous text", func() {
+		_, clear := classifier.Classify("crash panic error")
+		_, ambiguous := classifier.Classify("promise pipeline")
+		Expect(ambiguous).To(BeNumerically("<", clear))
+	})
+
+	It("should return no type for text without known words", func() {
+		typeName, confidence := classifier.Classify("zzz qqq")
+		Expect(typeName).To(BeEmpty())
+		Expect(confidence).To(BeZero())
+
+		typeName, _ = NewClassifier().Classify("crash")
+		Expect(typeName).To(BeEmpty())
+	})
+
+	It("should round trip through a saved model", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "model.json")
+		Expect(classifier.Save(path)).To(Succeed())
+
+		loaded, err := LoadClassifier(path)
+		Expect(err).NotTo(HaveOccurred())
+		expectedType, expectedConfidence := classifier.Classify("crash in controller")
+		typeName, confidence := loaded.Classify("crash in controller")
+		Expect(typeName).To(Equal(expectedType))
+		Expect(confidence).To(BeNumerically("~", expectedConfidence, 1e-9))
+	})
+})
@@ -0,0 +1,309 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type Config struct {
+	Organization string          `json:"organization"`
+	Projects     []ProjectConfig `json:"projects"`
+	// SchemaCheckInterval is how often the project schemas are fetched again,
+	// swapped in and validated against the configuration. Zero disables it.
+	SchemaCheckInterval Duration         `json:"schemaCheckInterval"`
+	IssueTypes          IssueTypesConfig `json:"issueTypes"`
+	// BreakingChangeLabel is added to issues and pull requests detected as
+	// breaking changes. The label must exist in the repository.
+	BreakingChangeLabel string           `json:"breakingChangeLabel"`
+	Classifier          ClassifierConfig `json:"classifier"`
+	TitleLint           TitleLintConfig  `json:"titleLint"`
+	Bots                BotConfig        `json:"bots"`
+	LoopPrevention      LoopConfig       `json:"loopPrevention"`
+	// IssueBranchPattern is the branch naming convention used to start
+	// issues when a branch is created; see NewBranchConvention. Empty uses
+	// DefaultIssueBranchPattern.
+	IssueBranchPattern string `json:"issueBranchPattern"`
+}
+
+const (
+	TitleLintCheck  = "check"
+	TitleLintStatus = "status"
+)
+
+// TitleLintConfig reports whether pull request titles select a type. Mode is
+// TitleLintCheck for a check run, which needs a GitHub App token,
+// TitleLintStatus for a commit status, or empty to disable it.
+type TitleLintConfig struct {
+	Mode string `json:"mode"`
+	Name string `json:"name"`
+}
+
+// ClassifierConfig suggests a type for new issues whose title has none.
+type ClassifierConfig struct {
+	// Model is the path of a model written by cmd/train-classifier. Issues
+	// are not classified without one.
+	Model string `json:"model"`
+	// Threshold is the confidence from which the suggested type is set.
+	Threshold float64 `json:"threshold"`
+	// Suggest comments the suggestion on the issue when the confidence is
+	// below Threshold.
+	Suggest bool `json:"suggest"`
+}
+
+// IssueTypesConfig selects the issue type from the title prefix. Prefixes
+// applies to every repository in the organization; Repositories adds to or
+// overrides it for a single repository, keyed by its full name.
+type IssueTypesConfig struct {
+	Prefixes     TypePrefixes            `json:"prefixes"`
+	Repositories map[string]TypePrefixes `json:"repositories"`
+	// Matchers are tried in order to find the type in a title. When empty
+	// DefaultMatchers are used.
+	Matchers []MatcherConfig `json:"matchers"`
+	// Policy decides when a detected type replaces the current one; see
+	// TypePolicyTitle, TypePolicyHuman and TypePolicyEmpty.
+	Policy string `json:"policy"`
+}
+
+// TypePrefixes maps an issue type name to every title prefix that selects it.
+type TypePrefixes map[string][]string
+
+// Duration is a time.Duration written as a string such as "30m" in JSON.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+type ProjectConfig struct {
+	Number              int                 `json:"number"`
+	StatusField         string              `json:"statusField"`
+	Statuses            []string            `json:"statuses"`
+	DateFields          []DateMapping       `json:"dateFields"`
+	PullRequestStatuses PullRequestStatuses `json:"pullRequestStatuses"`
+	IssueStatuses       IssueStatuses       `json:"issueStatuses"`
+	Durations           []DurationMapping   `json:"durations"`
+	Calendar            CalendarConfig      `json:"calendar"`
+	Transitions         TransitionPolicy    `json:"transitions"`
+	// TypeField is a single select field set to the type detected from the
+	// title, with one option per type name.
+	TypeField string `json:"typeField"`
+	// AreaField is a single select field set from the conventional commit
+	// scope. ScopeAliases maps scopes to option names where they differ.
+	AreaField    string            `json:"areaField"`
+	ScopeAliases map[string]string `json:"scopeAliases"`
+	// BreakingChangeField is a single select field set to BreakingChangeOption
+	// on items detected as breaking changes.
+	BreakingChangeField  string `json:"breakingChangeField"`
+	BreakingChangeOption string `json:"breakingChangeOption"`
+	// Filter selects the issues and pull requests added to the project.
+	Filter  ItemFilter    `json:"filter"`
+	Ranking RankingConfig `json:"ranking"`
+}
+
+// PullRequestStatuses names the status an item moves to at each point of a
+// pull request's life. An empty status leaves the item where it is.
+type PullRequestStatuses struct {
+	// Started is used for issues when a branch named after them is created
+	// or a pull request referencing them is opened. Items further along the
+	// board are left alone. Empty disables it.
+	Started string `json:"started"`
+	// Draft is used for pull requests opened or converted to draft.
+	Draft string `json:"draft"`
+	// InReview is used when a pull request is opened ready for review,
+	// marked ready or has a review requested.
+	InReview string `json:"inReview"`
+	Merged   string `json:"merged"`
+	// Closed is used for items of pull requests closed without merging
+	// that had no status before the pull request moved them. Items the
+	// automation did not move, or that were moved by hand since, stay where
+	// they are.
+	Closed string `json:"closed"`
+}
+
+// IssueStatuses names the status an issue's item moves to when the issue is
+// closed or reopened. An empty status leaves the item where it is; none are
+// set by default. A status such as "Won't do" for NotPlanned must also be an
+// option on the board and listed in ProjectConfig.Statuses.
+type IssueStatuses struct {
+	Completed  string `json:"completed"`
+	NotPlanned string `json:"notPlanned"`
+	// Reopened should come earlier in ProjectConfig.Statuses than the closed
+	// statuses so the move is treated as a reopening.
+	Reopened string `json:"reopened"`
+}
+
+// TransitionPolicy describes what happens to an item's fields when it moves
+// to a status that comes earlier in ProjectConfig.Statuses.
+type TransitionPolicy struct {
+	ClearFields      []string `json:"clearFields"`
+	ReopenFrom       []string `json:"reopenFrom"`
+	ReopenCountField string   `json:"reopenCountField"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Organization:        "syntasso",
+		Projects:            []ProjectConfig{DefaultProjectConfig(4)},
+		SchemaCheckInterval: Duration{time.Hour},
+		IssueTypes: IssueTypesConfig{
+			Prefixes: DefaultTypePrefixes(),
+			Matchers: DefaultMatchers(),
+			Policy:   TypePolicyHuman,
+		},
+		Classifier: ClassifierConfig{
+			Threshold: 0.8,
+		},
+		TitleLint: TitleLintConfig{
+			Name: "PR title",
+		},
+		Bots: BotConfig{
+			Mode: BotInclude,
+		},
+		LoopPrevention: LoopConfig{
+			EchoWindow:    Duration{time.Minute},
+			MaxChainDepth: 3,
+		},
+	}
+}
+
+func DefaultProjectConfig(number int) ProjectConfig {
+	return ProjectConfig{
+		Number:      number,
+		StatusField: "Status",
+		Statuses:    []string{"Todo", "In progress", "Done"},
+		DateFields: []DateMapping{
+			{Status: "In progress", Field: "Start date", Mode: DateSetOnce},
+			{Status: "Done", Field: "End date", Mode: DateSetOnce},
+		},
+		PullRequestStatuses: PullRequestStatuses{
+			Started: "In progress",
+			Draft:   "In progress",
+			Merged:  "Done",
+			Closed:  "Todo",
+		},
+		Transitions: TransitionPolicy{
+			ClearFields: []string{"End date"},
+			ReopenFrom:  []string{"Done"},
+		},
+		BreakingChangeOption: "Yes",
+	}
+}
+
+// LoadConfig reads the JSON configuration at path. Settings missing from a
+// project fall back to DefaultProjectConfig; an empty path returns
+// DefaultConfig.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		return DefaultConfig(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Projects shadows Config.Projects so each project can be decoded on top
+	// of its own defaults.
+	raw := struct {
+		*Config
+		Projects []json.RawMessage `json:"projects"`
+	}{
+		Config: &Config{
+			SchemaCheckInterval: DefaultConfig().SchemaCheckInterval,
+			Classifier:          DefaultConfig().Classifier,
+			TitleLint:           DefaultConfig().TitleLint,
+			Bots:                DefaultConfig().Bots,
+			LoopPrevention:      DefaultConfig().LoopPrevention,
+		},
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	config := raw.Config
+	if len(config.IssueTypes.Prefixes) == 0 {
+		config.IssueTypes.Prefixes = DefaultTypePrefixes()
+	}
+	if config.IssueTypes.Policy == "" {
+		config.IssueTypes.Policy = TypePolicyHuman
+	}
+	for i, rawProject := range raw.Projects {
+		project := DefaultProjectConfig(0)
+		// Decoding into the default slice would merge each configured date
+		// field into the default entry at the same index.
+		defaultDateFields := project.DateFields
+		project.DateFields = nil
+		if err := json.Unmarshal(rawProject, &project); err != nil {
+			return nil, fmt.Errorf("parsing project %d in %s: %w", i, path, err)
+		}
+		if project.DateFields == nil {
+			project.DateFields = defaultDateFields
+		}
+		for j := range project.DateFields {
+			if project.DateFields[j].Mode == "" {
+				project.DateFields[j].Mode = DateSetOnce
+			}
+		}
+		config.Projects = append(config.Projects, project)
+	}
+
+	if config.Organization == "" {
+		return nil, fmt.Errorf("%s: organization is required", path)
+	}
+	if _, err := NewTypeMappingFromConfig(config.IssueTypes); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := validateTypePolicy(config.IssueTypes.Policy); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := NewBranchConvention(config.IssueBranchPattern); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if config.Classifier.Threshold < 0 || config.Classifier.Threshold > 1 {
+		return nil, fmt.Errorf("%s: classifier threshold must be between 0 and 1", path)
+	}
+	switch config.TitleLint.Mode {
+	case "", TitleLintCheck, TitleLintStatus:
+	default:
+		return nil, fmt.Errorf("%s: unknown title lint mode %q", path, config.TitleLint.Mode)
+	}
+	if config.LoopPrevention.MaxChainDepth < 1 {
+		return nil, fmt.Errorf("%s: loop prevention max chain depth must be at least 1", path)
+	}
+	if err := config.Bots.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, project := range config.Projects {
+		if project.Number == 0 {
+			return nil, fmt.Errorf("%s: every project needs a number", path)
+		}
+		if err := project.Filter.validate(); err != nil {
+			return nil, fmt.Errorf("%s: project %d: %w", path, project.Number, err)
+		}
+		if err := project.Ranking.validate(); err != nil {
+			return nil, fmt.Errorf("%s: project %d: %w", path, project.Number, err)
+		}
+		if _, err := NewCalendar(project.Calendar); err != nil {
+			return nil, fmt.Errorf("%s: project %d: %w", path, project.Number, err)
+		}
+		for _, mapping := range project.DateFields {
+			if mapping.Mode != DateSetOnce && mapping.Mode != DateOverwrite {
+				return nil, fmt.Errorf("%s: project %d: date field %q has unknown mode %q", path, project.Number, mapping.Field, mapping.Mode)
+			}
+		}
+	}
+	return config, nil
+}
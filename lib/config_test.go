@@ -0,0 +1,106 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadConfig", func() {
+	writeConfig := func(content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "config.json")
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	It("should return the default configuration without a path", func() {
+		config, err := LoadConfig("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(Equal(DefaultConfig()))
+	})
+
+	It("should fill in project defaults that are not set", func() {
+		config, err := LoadConfig(writeConfig(`{
+			"organization": "acme",
+			"projects": [{"number": 7, "transitions": {"reopenCountField": "Reopened"}}]
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Organization).To(Equal("acme"))
+		Expect(config.Projects).To(HaveLen(1))
+
+		project := config.Projects[0]
+		Expect(project.Number).To(Equal(7))
+		Expect(project.StatusField).To(Equal("Status"))
+		Expect(project.Transitions.ClearFields).To(Equal([]string{"End date"}))
+		Expect(project.Transitions.ReopenCountField).To(Equal("Reopened"))
+	})
+
+	It("should reject projects without a number", func() {
+		_, err := LoadConfig(writeConfig(`{"organization": "acme", "projects": [{}]}`))
+		Expect(err).To(MatchError(ContainSubstring("number")))
+	})
+	It("should reject unknown date field modes", func() {
+		_, err := LoadConfig(writeConfig(`{
+			"organization": "acme",
+			"projects": [{"number": 1, "dateFields": [{"status": "Done", "field": "End date", "mode": "sometimes"}]}]
+		}`))
+		Expect(err).To(MatchError(ContainSubstring("unknown mode")))
+	})
+	It("should default omitted date field modes to set-once", func() {
+		config, err := LoadConfig(writeConfig(`{
+			"organization": "acme",
+			"projects": [{"number": 1, "dateFields": [
+				{"status": "In progress", "field": "Start date", "mode": "overwrite"},
+				{"status": "Done", "field": "End date", "mode": "overwrite"},
+				{"status": "Review", "field": "Review date"}
+			]}]
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Projects[0].DateFields).To(Equal([]DateMapping{
+			{Status: "In progress", Field: "Start date", Mode: DateOverwrite},
+			{Status: "Done", Field: "End date", Mode: DateOverwrite},
+			{Status: "Review", Field: "Review date", Mode: DateSetOnce},
+		}))
+	})
+	It("should keep the default date fields when none are configured", func() {
+		config, err := LoadConfig(writeConfig(`{"organization": "acme", "projects": [{"number": 1}]}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Projects[0].DateFields).To(Equal(DefaultProjectConfig(1).DateFields))
+	})
+	It("should reject malformed repository filters", func() {
+		_, err := LoadConfig(writeConfig(`{
+			"organization": "acme",
+			"projects": [{"number": 1, "filter": {"repositories": ["acme/[kratix"]}}]
+		}`))
+		Expect(err).To(MatchError(ContainSubstring("repository filter")))
+	})
+	It("should parse the schema check interval", func() {
+		config, err := LoadConfig(writeConfig(`{"organization": "acme", "schemaCheckInterval": "15m"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.SchemaCheckInterval.Duration).To(Equal(15 * time.Minute))
+
+		_, err = LoadConfig(writeConfig(`{"organization": "acme", "schemaCheckInterval": "soon"}`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject classifier thresholds outside 0 to 1", func() {
+		_, err := LoadConfig(writeConfig(`{"organization": "acme", "classifier": {"threshold": 80}}`))
+		Expect(err).To(MatchError(ContainSubstring("classifier threshold")))
+
+		config, err := LoadConfig(writeConfig(`{"organization": "acme", "classifier": {"threshold": 0.5}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Classifier.Threshold).To(Equal(0.5))
+	})
+
+	It("should default and validate the issue type policy", func() {
+		config, err := LoadConfig(writeConfig(`{"organization": "acme"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.IssueTypes.Policy).To(Equal(TypePolicyHuman))
+
+		_, err = LoadConfig(writeConfig(`{"organization": "acme", "issueTypes": {"policy": "whoever"}}`))
+		Expect(err).To(MatchError(ContainSubstring("unknown issue type policy")))
+	})
+})
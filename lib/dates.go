@@ -0,0 +1,32 @@
+package lib
+
+const (
+	// DateSetOnce only stamps the field while it is empty.
+	DateSetOnce = "set-once"
+	// DateOverwrite stamps the field every time the item enters the status.
+	DateOverwrite = "overwrite"
+)
+
+// DateMapping records the date an item entered Status in the date field Field.
+type DateMapping struct {
+	Status string `json:"status"`
+	Field  string `json:"field"`
+	// Mode is DateSetOnce or DateOverwrite, and DateSetOnce when omitted.
+	Mode string `json:"mode"`
+}
+
+// DateFieldsToStamp returns the date fields to set for an item that is now in
+// status, given the item's current field values.
+func (p *ProjectConfig) DateFieldsToStamp(status string, values map[string]string) []string {
+	var fields []string
+	for _, mapping := range p.DateFields {
+		if mapping.Status != status {
+			continue
+		}
+		if mapping.Mode == DateSetOnce && values[mapping.Field] != "" {
+			continue
+		}
+		fields = append(fields, mapping.Field)
+	}
+	return fields
+}
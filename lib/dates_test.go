@@ -0,0 +1,36 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DateFieldsToStamp", func() {
+	var config ProjectConfig
+
+	BeforeEach(func() {
+		config = DefaultProjectConfig(1)
+		config.DateFields = append(config.DateFields,
+			DateMapping{Status: "In review", Field: "Review date", Mode: DateOverwrite},
+			DateMapping{Status: "Done", Field: "Released", Mode: DateSetOnce},
+		)
+	})
+
+	It("should stamp set-once fields only while they are empty", func() {
+		Expect(config.DateFieldsToStamp("In progress", map[string]string{})).To(Equal([]string{"Start date"}))
+		Expect(config.DateFieldsToStamp("In progress", map[string]string{"Start date": "2024-05-01"})).To(BeEmpty())
+	})
+
+	It("should always stamp overwrite fields", func() {
+		Expect(config.DateFieldsToStamp("In review", map[string]string{"Review date": "2024-05-01"})).To(Equal([]string{"Review date"}))
+	})
+
+	It("should return every field mapped to the status", func() {
+		Expect(config.DateFieldsToStamp("Done", map[string]string{"Released": "2024-05-01"})).To(Equal([]string{"End date"}))
+		Expect(config.DateFieldsToStamp("Done", nil)).To(Equal([]string{"End date", "Released"}))
+	})
+
+	It("should return nothing for unmapped statuses", func() {
+		Expect(config.DateFieldsToStamp("Todo", nil)).To(BeEmpty())
+	})
+})
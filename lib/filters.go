@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ItemFilter decides which issues and pull requests a project takes. Empty
+// lists allow everything; exclusions win over inclusions. Repository entries
+// are globs matched against the full name, such as "syntasso/kratix-*".
+type ItemFilter struct {
+	Repositories        []string `json:"repositories"`
+	ExcludeRepositories []string `json:"excludeRepositories"`
+	// Labels must all be present; ExcludeLabels must all be absent.
+	Labels         []string `json:"labels"`
+	ExcludeLabels  []string `json:"excludeLabels"`
+	Authors        []string `json:"authors"`
+	ExcludeAuthors []string `json:"excludeAuthors"`
+	// IssueTypes lists the type names accepted. Items without a type are
+	// only accepted when the list is empty.
+	IssueTypes []string `json:"issueTypes"`
+}
+
+// FilterSubject is what an ItemFilter looks at.
+type FilterSubject struct {
+	Repository string
+	Author     string
+	Labels     []string
+	IssueType  string
+}
+
+// Allows reports whether the filter accepts subject and, when it does not,
+// why.
+func (f ItemFilter) Allows(subject FilterSubject) (bool, string) {
+	if len(f.Repositories) > 0 && !matchesAny(f.Repositories, subject.Repository) {
+		return false, fmt.Sprintf("repository %s is not allowed", subject.Repository)
+	}
+	if matchesAny(f.ExcludeRepositories, subject.Repository) {
+		return false, fmt.Sprintf("repository %s is excluded", subject.Repository)
+	}
+	for _, label := range f.Labels {
+		if !containsFold(subject.Labels, label) {
+			return false, fmt.Sprintf("label %q is missing", label)
+		}
+	}
+	for _, label := range f.ExcludeLabels {
+		if containsFold(subject.Labels, label) {
+			return false, fmt.Sprintf("label %q is excluded", label)
+		}
+	}
+	if len(f.Authors) > 0 && !containsFold(f.Authors, subject.Author) {
+		return false, fmt.Sprintf("author %s is not allowed", subject.Author)
+	}
+	if containsFold(f.ExcludeAuthors, subject.Author) {
+		return false, fmt.Sprintf("author %s is excluded", subject.Author)
+	}
+	if len(f.IssueTypes) > 0 && !containsFold(f.IssueTypes, subject.IssueType) {
+		return false, fmt.Sprintf("issue type %q is not allowed", subject.IssueType)
+	}
+	return true, ""
+}
+
+// IsEmpty reports whether the filter allows everything.
+func (f ItemFilter) IsEmpty() bool {
+	return len(slices.Concat(f.Repositories, f.ExcludeRepositories, f.Labels, f.ExcludeLabels, f.Authors, f.ExcludeAuthors, f.IssueTypes)) == 0
+}
+
+func (f ItemFilter) validate() error {
+	for _, pattern := range slices.Concat(f.Repositories, f.ExcludeRepositories) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("repository filter %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func matchesAny(patterns []string, repository string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(repository)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
@@ -0,0 +1,58 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ItemFilter", func() {
+	subject := FilterSubject{
+		Repository: "syntasso/kratix",
+		Author:     "derik",
+		Labels:     []string{"bug", "Team: Platform"},
+		IssueType:  "Bug",
+	}
+
+	It("should allow everything when empty", func() {
+		allowed, _ := ItemFilter{}.Allows(subject)
+		Expect(allowed).To(BeTrue())
+		Expect(ItemFilter{}.IsEmpty()).To(BeTrue())
+		Expect(ItemFilter{ExcludeAuthors: []string{"dependabot[bot]"}}.IsEmpty()).To(BeFalse())
+	})
+
+	It("should match repositories against globs", func() {
+		allowed, _ := ItemFilter{Repositories: []string{"syntasso/kratix*"}}.Allows(subject)
+		Expect(allowed).To(BeTrue())
+
+		allowed, reason := ItemFilter{Repositories: []string{"syntasso/docs"}}.Allows(subject)
+		Expect(allowed).To(BeFalse())
+		Expect(reason).To(Equal("repository syntasso/kratix is not allowed"))
+
+		allowed, _ = ItemFilter{Repositories: []string{"syntasso/*"}, ExcludeRepositories: []string{"*/kratix"}}.Allows(subject)
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("should require and exclude labels case-insensitively", func() {
+		allowed, _ := ItemFilter{Labels: []string{"team: platform", "bug"}}.Allows(subject)
+		Expect(allowed).To(BeTrue())
+
+		allowed, reason := ItemFilter{Labels: []string{"bug", "triaged"}}.Allows(subject)
+		Expect(allowed).To(BeFalse())
+		Expect(reason).To(Equal(`label "triaged" is missing`))
+
+		allowed, _ = ItemFilter{ExcludeLabels: []string{"BUG"}}.Allows(subject)
+		Expect(allowed).To(BeFalse())
+	})
+
+	It("should filter authors and issue types", func() {
+		allowed, _ := ItemFilter{Authors: []string{"someone-else"}}.Allows(subject)
+		Expect(allowed).To(BeFalse())
+		allowed, _ = ItemFilter{ExcludeAuthors: []string{"derik"}}.Allows(subject)
+		Expect(allowed).To(BeFalse())
+
+		allowed, _ = ItemFilter{IssueTypes: []string{"Bug", "Feature"}}.Allows(subject)
+		Expect(allowed).To(BeTrue())
+		allowed, _ = ItemFilter{IssueTypes: []string{"Feature"}}.Allows(FilterSubject{Repository: "syntasso/kratix"})
+		Expect(allowed).To(BeFalse())
+	})
+})
@@ -3,16 +3,25 @@ package lib
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 )
 
+const githubAPIURL = "https://api.github.com"
+
 type GithubClient struct {
-	client *githubv4.Client
-	ctx    context.Context
+	client     *githubv4.Client
+	httpClient *http.Client
+	baseURL    string
+	endpoint   string
+	ctx        context.Context
+	loopGuard  *LoopGuard
 }
 
 type ProjectDetails struct {
@@ -25,20 +34,43 @@ type ProjectDetails struct {
 	// Fields             map[string]Node
 }
 
+// FieldID returns the ID of the field called name, whatever its type.
+func (p *ProjectDetails) FieldID(name string) (string, bool) {
+	switch field := p.FieldsByName[name].(type) {
+	case Field:
+		return field.ID, true
+	case SingleSelectField:
+		return field.ID, true
+	}
+	return "", false
+}
+
 type SingleSelectField struct {
 	ID      string
 	Name    string
 	Options map[string]Field `json:"options,omitempty"`
 }
 
+// OptionByName returns the option called name.
+func (f SingleSelectField) OptionByName(name string) (Field, bool) {
+	for _, option := range f.Options {
+		if option.Name == name {
+			return option, true
+		}
+	}
+	return Field{}, false
+}
+
 type Field struct {
-	ID   string
-	Name string
+	ID       string
+	Name     string
+	DataType string `json:"dataType,omitempty"`
 }
 
 type UpdateIssueIssueTypeInput struct {
-	IssueID     githubv4.ID  `json:"issueId"`
-	IssueTypeID *githubv4.ID `json:"issueTypeId,omitempty"`
+	IssueID githubv4.ID `json:"issueId"`
+	// IssueTypeID is sent as null to clear the type.
+	IssueTypeID *githubv4.ID `json:"issueTypeId"`
 }
 
 type AddAssigneesToAssignableInput struct {
@@ -47,9 +79,8 @@ type AddAssigneesToAssignableInput struct {
 }
 
 type ProjectItem struct {
-	Status    string
-	StartDate string
-	EndDate   string
+	ID     string
+	Values map[string]string
 }
 
 func NewGithubClient() *GithubClient {
@@ -58,10 +89,40 @@ func NewGithubClient() *GithubClient {
 	)
 	httpClient := oauth2.NewClient(context.Background(), src)
 
-	client := githubv4.NewClient(httpClient)
+	return newGithubClient(httpClient, githubAPIURL)
+}
+
+// newGithubClient talks to the REST API at baseURL and the GraphQL API at
+// baseURL/graphql.
+func newGithubClient(httpClient *http.Client, baseURL string) *GithubClient {
+	endpoint := baseURL + "/graphql"
 	return &GithubClient{
-		client: client,
-		ctx:    context.Background(),
+		client:     githubv4.NewEnterpriseClient(endpoint, httpClient),
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		endpoint:   endpoint,
+		ctx:        context.Background(),
+	}
+}
+
+// TrackWrites records every project item field the client writes in guard.
+func (g *GithubClient) TrackWrites(guard *LoopGuard) {
+	g.loopGuard = guard
+}
+
+// recordWrite notes a write that is about to be sent and returns when, for
+// forgetWrite should the mutation fail.
+func (g *GithubClient) recordWrite(itemID, fieldID string) time.Time {
+	now := time.Now()
+	if g.loopGuard != nil {
+		g.loopGuard.Record(itemID, fieldID, now)
+	}
+	return now
+}
+
+func (g *GithubClient) forgetWrite(itemID, fieldID string, recordedAt time.Time) {
+	if g.loopGuard != nil {
+		g.loopGuard.Forget(itemID, fieldID, recordedAt)
 	}
 }
 
@@ -83,31 +144,46 @@ func (g *GithubClient) UpdateProjectItem(projectID, itemID, fieldID string, valu
 		Value:     value,
 	}
 
-	return g.client.Mutate(g.ctx, &query, input, nil)
+	recordedAt := g.recordWrite(itemID, fieldID)
+	if err := g.client.Mutate(g.ctx, &query, input, nil); err != nil {
+		g.forgetWrite(itemID, fieldID, recordedAt)
+		return err
+	}
+	return nil
 }
 
-func (g *GithubClient) FetchStatusAndStartDate(projectItemID string) (*ProjectItem, error) {
+func (g *GithubClient) ClearProjectItemField(projectID, itemID, fieldID string) error {
+	var mutation struct {
+		ClearProjectV2ItemFieldValue struct {
+			ProjectV2Item struct {
+				ID githubv4.String
+			} `graphql:"projectV2Item"`
+		} `graphql:"clearProjectV2ItemFieldValue(input: $input)"`
+	}
+
+	input := githubv4.ClearProjectV2ItemFieldValueInput{
+		ProjectID: githubv4.ID(projectID),
+		ItemID:    githubv4.ID(itemID),
+		FieldID:   githubv4.ID(fieldID),
+	}
+
+	recordedAt := g.recordWrite(itemID, fieldID)
+	if err := g.client.Mutate(g.ctx, &mutation, input, nil); err != nil {
+		g.forgetWrite(itemID, fieldID, recordedAt)
+		return err
+	}
+	return nil
+}
+
+// FetchProjectItem returns the current value of every field set on the item,
+// keyed by field name. Single select values are the option name and numbers
+// are formatted with strconv.
+func (g *GithubClient) FetchProjectItem(projectItemID string) (*ProjectItem, error) {
 	var query struct {
 		Node struct {
-			ID            githubv4.String
 			ProjectV2Item struct {
-				CurrentStatus struct {
-					ProjectV2ItemFieldSingleSelectValue struct {
-						Name githubv4.String
-					} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
-				} `graphql:"currentStatus: fieldValueByName(name: \"Status\")"`
-				CurrentStartDate struct {
-					ProjectV2ItemFieldDateValue struct {
-						Date      githubv4.String
-						UpdatedAt githubv4.Date
-					} `graphql:"... on ProjectV2ItemFieldDateValue"`
-				} `graphql:"currentStartDate: fieldValueByName(name: \"Start date\")"`
-				CurrentEndDate struct {
-					ProjectV2ItemFieldDateValue struct {
-						Date      githubv4.String
-						UpdatedAt githubv4.Date
-					} `graphql:"... on ProjectV2ItemFieldDateValue"`
-				} `graphql:"currentEndDate: fieldValueByName(name: \"End date\")"`
+				ID          githubv4.String
+				FieldValues itemFieldValues `graphql:"fieldValues(first: 100)"`
 			} `graphql:"... on ProjectV2Item"`
 		} `graphql:"node(id: $projectItemID)"`
 	}
@@ -119,17 +195,62 @@ func (g *GithubClient) FetchStatusAndStartDate(projectItemID string) (*ProjectIt
 		return nil, err
 	}
 
-	startDateStr := query.Node.ProjectV2Item.CurrentStartDate.ProjectV2ItemFieldDateValue.Date
-	endDateStr := query.Node.ProjectV2Item.CurrentEndDate.ProjectV2ItemFieldDateValue.Date
 	return &ProjectItem{
-		Status:    string(query.Node.ProjectV2Item.CurrentStatus.ProjectV2ItemFieldSingleSelectValue.Name),
-		StartDate: string(startDateStr),
-		EndDate:   string(endDateStr),
+		ID:     string(query.Node.ProjectV2Item.ID),
+		Values: query.Node.ProjectV2Item.FieldValues.byName(),
 	}, nil
+}
+
+type itemFieldName struct {
+	ProjectV2FieldCommon struct {
+		Name githubv4.String
+	} `graphql:"... on ProjectV2FieldCommon"`
+}
+
+type itemFieldValues struct {
+	Nodes []struct {
+		ProjectV2ItemFieldSingleSelectValue struct {
+			Name  githubv4.String
+			Field itemFieldName
+		} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+		ProjectV2ItemFieldDateValue struct {
+			Date  githubv4.String
+			Field itemFieldName
+		} `graphql:"... on ProjectV2ItemFieldDateValue"`
+		ProjectV2ItemFieldNumberValue struct {
+			Number githubv4.Float
+			Field  itemFieldName
+		} `graphql:"... on ProjectV2ItemFieldNumberValue"`
+		ProjectV2ItemFieldTextValue struct {
+			Text  githubv4.String
+			Field itemFieldName
+		} `graphql:"... on ProjectV2ItemFieldTextValue"`
+	} `graphql:"nodes"`
+}
 
+func (v itemFieldValues) byName() map[string]string {
+	values := make(map[string]string)
+	for _, value := range v.Nodes {
+		switch {
+		case value.ProjectV2ItemFieldSingleSelectValue.Field.ProjectV2FieldCommon.Name != "":
+			values[string(value.ProjectV2ItemFieldSingleSelectValue.Field.ProjectV2FieldCommon.Name)] = string(value.ProjectV2ItemFieldSingleSelectValue.Name)
+		case value.ProjectV2ItemFieldDateValue.Field.ProjectV2FieldCommon.Name != "":
+			values[string(value.ProjectV2ItemFieldDateValue.Field.ProjectV2FieldCommon.Name)] = string(value.ProjectV2ItemFieldDateValue.Date)
+		case value.ProjectV2ItemFieldNumberValue.Field.ProjectV2FieldCommon.Name != "":
+			values[string(value.ProjectV2ItemFieldNumberValue.Field.ProjectV2FieldCommon.Name)] = strconv.FormatFloat(float64(value.ProjectV2ItemFieldNumberValue.Number), 'f', -1, 64)
+		case value.ProjectV2ItemFieldTextValue.Field.ProjectV2FieldCommon.Name != "":
+			values[string(value.ProjectV2ItemFieldTextValue.Field.ProjectV2FieldCommon.Name)] = string(value.ProjectV2ItemFieldTextValue.Text)
+		}
+	}
+	return values
 }
 
-func (g *GithubClient) ProjectDetails(organization string, projectNumber int) (*ProjectDetails, error) {
+func (g *GithubClient) ProjectDetails(organization string, projectNumber int, issueTypes IssueTypesConfig) (*ProjectDetails, error) {
+	typeMapping, err := NewTypeMappingFromConfig(issueTypes)
+	if err != nil {
+		return nil, err
+	}
+
 	var orgInfoQuery struct {
 		Organization struct {
 			ProjectV2 struct {
@@ -137,8 +258,9 @@ func (g *GithubClient) ProjectDetails(organization string, projectNumber int) (*
 				Fields struct {
 					Nodes []struct {
 						ProjectV2Field struct {
-							ID   githubv4.String
-							Name githubv4.String
+							ID       githubv4.String
+							Name     githubv4.String
+							DataType githubv4.ProjectV2FieldType
 						} `graphql:"... on ProjectV2Field"`
 						ProjectV2SingleSelectField struct {
 							ID      githubv4.String
@@ -173,7 +295,7 @@ func (g *GithubClient) ProjectDetails(organization string, projectNumber int) (*
 		"organization":  githubv4.String(organization),
 		"projectNumber": githubv4.Int(projectNumber),
 	}
-	err := g.client.Query(g.ctx, &orgInfoQuery, variables)
+	err = g.client.Query(g.ctx, &orgInfoQuery, variables)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +304,7 @@ func (g *GithubClient) ProjectDetails(organization string, projectNumber int) (*
 	projectDetails.ID = string(orgInfoQuery.Organization.ProjectV2.ID)
 	projectDetails.FieldsByName = make(map[string]interface{})
 	projectDetails.FieldsByID = make(map[string]interface{})
-	projectDetails.TypeMapping = NewTypeMapping()
+	projectDetails.TypeMapping = typeMapping
 
 	for _, field := range orgInfoQuery.Organization.ProjectV2.Fields.Nodes {
 		var fieldValue interface{}
@@ -192,8 +314,9 @@ func (g *GithubClient) ProjectDetails(organization string, projectNumber int) (*
 			fieldName = string(field.ProjectV2Field.Name)
 			fieldID = string(field.ProjectV2Field.ID)
 			fieldValue = Field{
-				ID:   string(field.ProjectV2Field.ID),
-				Name: string(field.ProjectV2Field.Name),
+				ID:       string(field.ProjectV2Field.ID),
+				Name:     string(field.ProjectV2Field.Name),
+				DataType: string(field.ProjectV2Field.DataType),
 			}
 		}
 
@@ -281,6 +404,8 @@ func (g *GithubClient) AddNodeToProject(projectID string, nodeID string) (string
 	return mutation.AddProjectV2ItemById.Item.ID, nil
 }
 
+// UpdateIssueType sets the issue's type, or clears it when issueTypeID is
+// empty.
 func (g *GithubClient) UpdateIssueType(issueID, issueTypeID string) error {
 	var mutation struct {
 		UpdateIssueIssueType struct {
@@ -291,8 +416,10 @@ func (g *GithubClient) UpdateIssueType(issueID, issueTypeID string) error {
 	}
 
 	input := UpdateIssueIssueTypeInput{
-		IssueID:     githubv4.ID(issueID),
-		IssueTypeID: githubv4.NewID(githubv4.ID(issueTypeID)),
+		IssueID: githubv4.ID(issueID),
+	}
+	if issueTypeID != "" {
+		input.IssueTypeID = githubv4.NewID(githubv4.ID(issueTypeID))
 	}
 
 	err := g.client.Mutate(g.ctx, &mutation, input, nil)
@@ -331,3 +458,514 @@ func (g *GithubClient) AssignPullRequestToUser(pullRequestNodeID, login string)
 	}
 	return g.client.Mutate(g.ctx, &mutation, input, nil)
 }
+
+func (g *GithubClient) AddLabelByName(owner, repository, labelableID, labelName string) error {
+	labelID, err := g.fetchLabelID(owner, repository, labelName)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		AddLabelsToLabelable struct {
+			ClientMutationID githubv4.String
+		} `graphql:"addLabelsToLabelable(input: $input)"`
+	}
+	input := githubv4.AddLabelsToLabelableInput{
+		LabelableID: githubv4.ID(labelableID),
+		LabelIDs:    []githubv4.ID{githubv4.ID(labelID)},
+	}
+	return g.client.Mutate(g.ctx, &mutation, input, nil)
+}
+
+func (g *GithubClient) RemoveLabelByName(owner, repository, labelableID, labelName string) error {
+	labelID, err := g.fetchLabelID(owner, repository, labelName)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		RemoveLabelsFromLabelable struct {
+			ClientMutationID githubv4.String
+		} `graphql:"removeLabelsFromLabelable(input: $input)"`
+	}
+	input := githubv4.RemoveLabelsFromLabelableInput{
+		LabelableID: githubv4.ID(labelableID),
+		LabelIDs:    []githubv4.ID{githubv4.ID(labelID)},
+	}
+	return g.client.Mutate(g.ctx, &mutation, input, nil)
+}
+
+func (g *GithubClient) fetchLabelID(owner, repository, labelName string) (string, error) {
+	var labelQuery struct {
+		Repository struct {
+			Label struct {
+				ID githubv4.String
+			} `graphql:"label(name: $labelName)"`
+		} `graphql:"repository(owner: $owner, name: $repository)"`
+	}
+	labelQueryVars := map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"repository": githubv4.String(repository),
+		"labelName":  githubv4.String(labelName),
+	}
+	if err := g.client.Query(g.ctx, &labelQuery, labelQueryVars); err != nil {
+		return "", err
+	}
+	if labelQuery.Repository.Label.ID == "" {
+		return "", fmt.Errorf("label %q not found in %s/%s", labelName, owner, repository)
+	}
+	return string(labelQuery.Repository.Label.ID), nil
+}
+
+// FetchIssueType returns the ID and name of the issue's current type, which
+// are empty when it has none.
+func (g *GithubClient) FetchIssueType(issueID string) (string, string, error) {
+	var query struct {
+		Node struct {
+			Issue struct {
+				IssueType struct {
+					ID   githubv4.String
+					Name githubv4.String
+				}
+			} `graphql:"... on Issue"`
+		} `graphql:"node(id: $issueID)"`
+	}
+	variables := map[string]interface{}{
+		"issueID": githubv4.ID(issueID),
+	}
+	if err := g.client.Query(g.ctx, &query, variables); err != nil {
+		return "", "", err
+	}
+	return string(query.Node.Issue.IssueType.ID), string(query.Node.Issue.IssueType.Name), nil
+}
+
+type typeEventFields struct {
+	Actor struct {
+		Login githubv4.String
+	}
+	IssueType struct {
+		ID githubv4.String
+	}
+}
+
+// FetchLastTypeChange returns the type set by the most recent issue type
+// added or changed event on the issue's timeline and the login that made it.
+// Both are empty when the type was never set.
+func (g *GithubClient) FetchLastTypeChange(issueID string) (string, string, error) {
+	var query struct {
+		Node struct {
+			Issue struct {
+				TimelineItems struct {
+					Nodes []struct {
+						IssueTypeAddedEvent   typeEventFields `graphql:"... on IssueTypeAddedEvent"`
+						IssueTypeChangedEvent typeEventFields `graphql:"... on IssueTypeChangedEvent"`
+					}
+				} `graphql:"timelineItems(last: 1, itemTypes: [ISSUE_TYPE_ADDED_EVENT, ISSUE_TYPE_CHANGED_EVENT])"`
+			} `graphql:"... on Issue"`
+		} `graphql:"node(id: $issueID)"`
+	}
+	variables := map[string]interface{}{
+		"issueID": githubv4.ID(issueID),
+	}
+	if err := g.client.Query(g.ctx, &query, variables); err != nil {
+		return "", "", err
+	}
+	for _, node := range query.Node.Issue.TimelineItems.Nodes {
+		event := node.IssueTypeAddedEvent
+		if event.IssueType.ID == "" {
+			event = node.IssueTypeChangedEvent
+		}
+		return string(event.IssueType.ID), string(event.Actor.Login), nil
+	}
+	return "", "", nil
+}
+
+type TypedIssue struct {
+	Title string
+	Body  string
+	Type  string
+}
+
+// FetchTypedIssues scans the limit most recent issues of the repository and
+// returns the ones that have an issue type.
+func (g *GithubClient) FetchTypedIssues(owner, repository string, limit int) ([]TypedIssue, error) {
+	var query struct {
+		Repository struct {
+			Issues struct {
+				Nodes []struct {
+					Title     githubv4.String
+					Body      githubv4.String
+					IssueType struct {
+						Name githubv4.String
+					}
+				} `graphql:"nodes"`
+				PageInfo struct {
+					EndCursor   githubv4.String
+					HasNextPage githubv4.Boolean
+				}
+			} `graphql:"issues(first: 100, after: $cursor, orderBy: {field: CREATED_AT, direction: DESC})"`
+		} `graphql:"repository(owner: $owner, name: $repository)"`
+	}
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"repository": githubv4.String(repository),
+		"cursor":     (*githubv4.String)(nil),
+	}
+
+	var issues []TypedIssue
+	scanned := 0
+	for scanned < limit {
+		if err := g.client.Query(g.ctx, &query, variables); err != nil {
+			return nil, err
+		}
+		for _, issue := range query.Repository.Issues.Nodes {
+			if scanned == limit {
+				break
+			}
+			scanned++
+			if issue.IssueType.Name == "" {
+				continue
+			}
+			issues = append(issues, TypedIssue{
+				Title: string(issue.Title),
+				Body:  string(issue.Body),
+				Type:  string(issue.IssueType.Name),
+			})
+		}
+		if !query.Repository.Issues.PageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = githubv4.NewString(query.Repository.Issues.PageInfo.EndCursor)
+	}
+	return issues, nil
+}
+
+func (g *GithubClient) AddComment(subjectID, body string) error {
+	var mutation struct {
+		AddComment struct {
+			ClientMutationID githubv4.String
+		} `graphql:"addComment(input: $input)"`
+	}
+	input := githubv4.AddCommentInput{
+		SubjectID: githubv4.ID(subjectID),
+		Body:      githubv4.String(body),
+	}
+	return g.client.Mutate(g.ctx, &mutation, input, nil)
+}
+
+// ClosingIssue is an issue a pull request will close, with what project
+// filters look at.
+type ClosingIssue struct {
+	ID         string
+	Repository string
+	Title      string
+	Author     string
+	Labels     []string
+	IssueType  string
+}
+
+// FetchClosingIssues returns the issues a pull request will close, in any
+// repository.
+func (g *GithubClient) FetchClosingIssues(pullRequestID string) ([]ClosingIssue, error) {
+	var query struct {
+		Node struct {
+			PullRequest struct {
+				ClosingIssuesReferences struct {
+					Nodes []struct {
+						ID         githubv4.String
+						Title      githubv4.String
+						Repository struct {
+							NameWithOwner githubv4.String
+						}
+						Author struct {
+							Login githubv4.String
+						}
+						Labels struct {
+							Nodes []struct {
+								Name githubv4.String
+							}
+						} `graphql:"labels(first: 100)"`
+						IssueType struct {
+							Name githubv4.String
+						}
+					}
+				} `graphql:"closingIssuesReferences(first: 50)"`
+			} `graphql:"... on PullRequest"`
+		} `graphql:"node(id: $pullRequestID)"`
+	}
+	variables := map[string]interface{}{
+		"pullRequestID": githubv4.ID(pullRequestID),
+	}
+	if err := g.client.Query(g.ctx, &query, variables); err != nil {
+		return nil, err
+	}
+
+	var issues []ClosingIssue
+	for _, node := range query.Node.PullRequest.ClosingIssuesReferences.Nodes {
+		issue := ClosingIssue{
+			ID:         string(node.ID),
+			Repository: string(node.Repository.NameWithOwner),
+			Title:      string(node.Title),
+			Author:     string(node.Author.Login),
+			IssueType:  string(node.IssueType.Name),
+		}
+		for _, label := range node.Labels.Nodes {
+			issue.Labels = append(issue.Labels, string(label.Name))
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// FetchIssueID returns the node ID of an issue by its number. It fails for
+// numbers that belong to pull requests.
+func (g *GithubClient) FetchIssueID(owner, repository string, number int) (string, error) {
+	var query struct {
+		Repository struct {
+			Issue struct {
+				ID githubv4.String
+			} `graphql:"issue(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $repository)"`
+	}
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(owner),
+		"repository": githubv4.String(repository),
+		"number":     githubv4.Int(number),
+	}
+	if err := g.client.Query(g.ctx, &query, variables); err != nil {
+		return "", err
+	}
+	return string(query.Repository.Issue.ID), nil
+}
+
+// FetchProjectItemID returns the item of an issue or pull request in the
+// project, or an empty ID when it is not on the board.
+func (g *GithubClient) FetchProjectItemID(projectID, contentID string) (string, error) {
+	type projectItems struct {
+		Nodes []struct {
+			ID      githubv4.String
+			Project struct {
+				ID githubv4.String
+			}
+		}
+	}
+	var query struct {
+		Node struct {
+			Issue struct {
+				ProjectItems projectItems `graphql:"projectItems(first: 50)"`
+			} `graphql:"... on Issue"`
+			PullRequest struct {
+				ProjectItems projectItems `graphql:"projectItems(first: 50)"`
+			} `graphql:"... on PullRequest"`
+		} `graphql:"node(id: $contentID)"`
+	}
+	variables := map[string]interface{}{
+		"contentID": githubv4.ID(contentID),
+	}
+	if err := g.client.Query(g.ctx, &query, variables); err != nil {
+		return "", err
+	}
+
+	nodes := append(query.Node.Issue.ProjectItems.Nodes, query.Node.PullRequest.ProjectItems.Nodes...)
+	for _, item := range nodes {
+		if string(item.Project.ID) == projectID {
+			return string(item.ID), nil
+		}
+	}
+	return "", nil
+}
+
+func (g *GithubClient) ArchiveProjectItem(projectID, itemID string) error {
+	var mutation struct {
+		ArchiveProjectV2Item struct {
+			Item struct {
+				ID githubv4.String
+			} `graphql:"item"`
+		} `graphql:"archiveProjectV2Item(input: $input)"`
+	}
+	input := githubv4.ArchiveProjectV2ItemInput{
+		ProjectID: githubv4.ID(projectID),
+		ItemID:    githubv4.ID(itemID),
+	}
+	return g.client.Mutate(g.ctx, &mutation, input, nil)
+}
+
+func (g *GithubClient) DeleteProjectItem(projectID, itemID string) error {
+	var mutation struct {
+		DeleteProjectV2Item struct {
+			DeletedItemID githubv4.ID `graphql:"deletedItemId"`
+		} `graphql:"deleteProjectV2Item(input: $input)"`
+	}
+	input := githubv4.DeleteProjectV2ItemInput{
+		ProjectID: githubv4.ID(projectID),
+		ItemID:    githubv4.ID(itemID),
+	}
+	return g.client.Mutate(g.ctx, &mutation, input, nil)
+}
+
+// FetchProjectItems lists every unarchived item of the project that has an
+// issue or pull request, mapping the content node ID to the item ID, and the
+// IDs of the archived items.
+func (g *GithubClient) FetchProjectItems(projectID string) (map[string]string, []string, error) {
+	var query struct {
+		Node struct {
+			ProjectV2 struct {
+				Items struct {
+					Nodes []struct {
+						ID         githubv4.String
+						IsArchived githubv4.Boolean
+						Content    struct {
+							Issue struct {
+								ID githubv4.String
+							} `graphql:"... on Issue"`
+							PullRequest struct {
+								ID githubv4.String
+							} `graphql:"... on PullRequest"`
+						}
+					}
+					PageInfo struct {
+						HasNextPage githubv4.Boolean
+						EndCursor   githubv4.String
+					}
+				} `graphql:"items(first: 100, after: $cursor)"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $projectID)"`
+	}
+	variables := map[string]interface{}{
+		"projectID": githubv4.ID(projectID),
+		"cursor":    (*githubv4.String)(nil),
+	}
+
+	items := make(map[string]string)
+	var archived []string
+	for {
+		if err := g.client.Query(g.ctx, &query, variables); err != nil {
+			return nil, nil, err
+		}
+		for _, item := range query.Node.ProjectV2.Items.Nodes {
+			if item.IsArchived {
+				archived = append(archived, string(item.ID))
+				continue
+			}
+			contentID := item.Content.Issue.ID
+			if contentID == "" {
+				contentID = item.Content.PullRequest.ID
+			}
+			if contentID != "" {
+				items[string(contentID)] = string(item.ID)
+			}
+		}
+		if !query.Node.ProjectV2.Items.PageInfo.HasNextPage {
+			return items, archived, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Node.ProjectV2.Items.PageInfo.EndCursor)
+	}
+}
+
+// ItemContent is the issue or pull request behind a project item. Kind is
+// "Issue" or "PullRequest".
+type ItemContent struct {
+	Kind       string
+	Repository string
+	Number     int
+	Title      string
+	Body       string
+	Labels     []string
+}
+
+// FetchItemContent returns the issue or pull request with the node ID, or
+// nil for any other kind of node, such as a draft issue.
+func (g *GithubClient) FetchItemContent(contentID string) (*ItemContent, error) {
+	type content struct {
+		Number     githubv4.Int
+		Title      githubv4.String
+		Body       githubv4.String
+		Repository struct {
+			NameWithOwner githubv4.String
+		}
+		Labels struct {
+			Nodes []struct {
+				Name githubv4.String
+			}
+		} `graphql:"labels(first: 100)"`
+	}
+	var query struct {
+		Node struct {
+			Typename    githubv4.String `graphql:"__typename"`
+			Issue       content         `graphql:"... on Issue"`
+			PullRequest content         `graphql:"... on PullRequest"`
+		} `graphql:"node(id: $contentID)"`
+	}
+	variables := map[string]interface{}{
+		"contentID": githubv4.ID(contentID),
+	}
+	if err := g.client.Query(g.ctx, &query, variables); err != nil {
+		return nil, err
+	}
+
+	var found content
+	switch query.Node.Typename {
+	case "Issue":
+		found = query.Node.Issue
+	case "PullRequest":
+		found = query.Node.PullRequest
+	default:
+		return nil, nil
+	}
+	labels := make([]string, 0, len(found.Labels.Nodes))
+	for _, label := range found.Labels.Nodes {
+		labels = append(labels, string(label.Name))
+	}
+	return &ItemContent{
+		Kind:       string(query.Node.Typename),
+		Repository: string(found.Repository.NameWithOwner),
+		Number:     int(found.Number),
+		Title:      string(found.Title),
+		Body:       string(found.Body),
+		Labels:     labels,
+	}, nil
+}
+
+// FetchOrderedItems returns every unarchived item of the project with its
+// field values, in board order.
+func (g *GithubClient) FetchOrderedItems(projectID string) ([]ProjectItem, error) {
+	var query struct {
+		Node struct {
+			ProjectV2 struct {
+				Items struct {
+					Nodes []struct {
+						ID          githubv4.String
+						IsArchived  githubv4.Boolean
+						FieldValues itemFieldValues `graphql:"fieldValues(first: 100)"`
+					}
+					PageInfo struct {
+						HasNextPage githubv4.Boolean
+						EndCursor   githubv4.String
+					}
+				} `graphql:"items(first: 100, after: $cursor, orderBy: {field: POSITION, direction: ASC})"`
+			} `graphql:"... on ProjectV2"`
+		} `graphql:"node(id: $projectID)"`
+	}
+	variables := map[string]interface{}{
+		"projectID": githubv4.ID(projectID),
+		"cursor":    (*githubv4.String)(nil),
+	}
+
+	var items []ProjectItem
+	for {
+		if err := g.client.Query(g.ctx, &query, variables); err != nil {
+			return nil, err
+		}
+		for _, item := range query.Node.ProjectV2.Items.Nodes {
+			// Archived items are off the board, so they take no rank.
+			if item.IsArchived {
+				continue
+			}
+			items = append(items, ProjectItem{ID: string(item.ID), Values: item.FieldValues.byName()})
+		}
+		if !query.Node.ProjectV2.Items.PageInfo.HasNextPage {
+			return items, nil
+		}
+		variables["cursor"] = githubv4.NewString(query.Node.ProjectV2.Items.PageInfo.EndCursor)
+	}
+}
@@ -0,0 +1,102 @@
+package lib
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GraphQL queries", func() {
+	var (
+		server   *httptest.Server
+		client   *GithubClient
+		response string
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(response))
+		}))
+		client = newGithubClient(server.Client(), server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("FetchItemContent", func() {
+		It("should return issues and pull requests", func() {
+			response = `{"data": {"node": {
+				"__typename": "PullRequest",
+				"number": 12,
+				"title": "feat!: drop v1",
+				"body": "BREAKING CHANGE: v1 is gone",
+				"repository": {"nameWithOwner": "syntasso/kratix"},
+				"labels": {"nodes": [{"name": "breaking"}]}
+			}}}`
+
+			content, err := client.FetchItemContent("pr-12")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(&ItemContent{
+				Kind:       "PullRequest",
+				Repository: "syntasso/kratix",
+				Number:     12,
+				Title:      "feat!: drop v1",
+				Body:       "BREAKING CHANGE: v1 is gone",
+				Labels:     []string{"breaking"},
+			}))
+		})
+
+		It("should return nothing for draft issues", func() {
+			response = `{"data": {"node": {"__typename": "DraftIssue"}}}`
+
+			content, err := client.FetchItemContent("draft-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(BeNil())
+		})
+	})
+
+	Describe("FetchProjectItems", func() {
+		It("should list archived items apart from the cached ones", func() {
+			response = `{"data": {"node": {"items": {
+				"nodes": [
+					{"id": "item-1", "isArchived": false, "content": {"id": "issue-1"}},
+					{"id": "item-2", "isArchived": true, "content": {"id": "issue-2"}},
+					{"id": "item-3", "isArchived": false, "content": {}}
+				],
+				"pageInfo": {"hasNextPage": false, "endCursor": ""}
+			}}}}`
+
+			items, archived, err := client.FetchProjectItems("project")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).To(Equal(map[string]string{"issue-1": "item-1"}))
+			Expect(archived).To(Equal([]string{"item-2"}))
+		})
+	})
+
+	Describe("FetchOrderedItems", func() {
+		It("should skip archived items", func() {
+			response = `{"data": {"node": {"items": {
+				"nodes": [
+					{"id": "item-1", "isArchived": false, "fieldValues": {"nodes": [
+						{"name": "Todo", "field": {"name": "Status"}}
+					]}},
+					{"id": "item-2", "isArchived": true, "fieldValues": {"nodes": [
+						{"name": "Todo", "field": {"name": "Status"}}
+					]}},
+					{"id": "item-3", "isArchived": false, "fieldValues": {"nodes": []}}
+				],
+				"pageInfo": {"hasNextPage": false, "endCursor": ""}
+			}}}}`
+
+			items, err := client.FetchOrderedItems("project")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).To(Equal([]ProjectItem{
+				{ID: "item-1", Values: map[string]string{"Status": "Todo"}},
+				{ID: "item-3", Values: map[string]string{}},
+			}))
+		})
+	})
+})
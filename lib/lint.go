@@ -0,0 +1,73 @@
+package lib
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type TitleLintResult struct {
+	Valid    bool
+	Type     string
+	Prefixes []string
+	// Format is the title format the configured matchers expect.
+	Format string
+	// Rule says which check the title failed.
+	Rule    string
+	Summary string
+}
+
+// LintTitle checks that a pull request title selects a type, and explains the
+// expected format, the rule it broke and the allowed prefixes when it does
+// not.
+func (tm *TypeMapping) LintTitle(title string) TitleLintResult {
+	if match, found := tm.Match(title); found {
+		return TitleLintResult{
+			Valid:   true,
+			Type:    match.Type,
+			Summary: fmt.Sprintf("The title selects the **%s** type.", match.Type),
+		}
+	}
+
+	result := TitleLintResult{
+		Prefixes: slices.Sorted(maps.Keys(tm.PrefixToType)),
+		Format:   tm.titleFormat(),
+		Rule:     "the title has no type prefix",
+	}
+	for _, matcher := range tm.Matchers {
+		if match, found := matcher.Match(title); found {
+			result.Rule = fmt.Sprintf("prefix %q is not a known type", match.Tag)
+			break
+		}
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "The title %q does not select a type: %s.\n\n", title, result.Rule)
+	fmt.Fprintf(&summary, "Expected format: %s\n\n", result.Format)
+	summary.WriteString("Allowed prefixes:\n\n")
+	for _, prefix := range result.Prefixes {
+		fmt.Fprintf(&summary, "- `%s` (%s)\n", prefix, tm.PrefixToType[prefix])
+	}
+	result.Summary = summary.String()
+	return result
+}
+
+// titleFormat describes the titles the matchers accept, in the order they are
+// tried.
+func (tm *TypeMapping) titleFormat() string {
+	var formats []string
+	for _, matcher := range tm.Matchers {
+		switch m := matcher.(type) {
+		case ConventionalMatcher:
+			formats = append(formats, "`prefix: description` or `prefix(scope): description`")
+		case BracketMatcher:
+			formats = append(formats, "`[Type] description`")
+		case *RegexMatcher:
+			formats = append(formats, fmt.Sprintf("a title matching `%s`", m.pattern))
+		case EmojiMatcher:
+			formats = append(formats, "a leading emoji or `:shortcode:`")
+		}
+	}
+	return strings.Join(formats, ", or ")
+}
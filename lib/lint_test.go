@@ -0,0 +1,40 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LintTitle", func() {
+	var typeMapping *TypeMapping
+
+	BeforeEach(func() {
+		typeMapping = NewTypeMapping()
+	})
+
+	It("should accept titles that select a type", func() {
+		result := typeMapping.LintTitle("feat(api): add endpoint")
+		Expect(result.Valid).To(BeTrue())
+		Expect(result.Type).To(Equal("Feature"))
+	})
+
+	It("should list the allowed prefixes for other titles", func() {
+		result := typeMapping.LintTitle("add endpoint")
+		Expect(result.Valid).To(BeFalse())
+		Expect(result.Prefixes).To(Equal([]string{"blog", "bug", "chore", "docs", "feat", "interrupt", "spike"}))
+		Expect(result.Summary).To(ContainSubstring("- `feat` (Feature)"))
+		Expect(result.Summary).To(ContainSubstring(`"add endpoint"`))
+	})
+
+	It("should explain the expected format and the rule that failed", func() {
+		result := typeMapping.LintTitle("add endpoint")
+		Expect(result.Rule).To(Equal("the title has no type prefix"))
+		Expect(result.Format).To(Equal("`prefix: description` or `prefix(scope): description`, or `[Type] description`"))
+		Expect(result.Summary).To(ContainSubstring("Expected format: " + result.Format))
+
+		result = typeMapping.LintTitle("feat-ure(api): add endpoint")
+		Expect(result.Valid).To(BeFalse())
+		Expect(result.Rule).To(Equal(`prefix "feat-ure" is not a known type`))
+		Expect(result.Summary).To(ContainSubstring(result.Rule))
+	})
+})
@@ -0,0 +1,148 @@
+package lib
+
+import (
+	"sync"
+	"time"
+)
+
+// LoopConfig stops the automation from reacting to its own changes.
+type LoopConfig struct {
+	// Logins are the accounts the automation acts as, such as
+	// "ghproject[bot]". Events they send are ignored, and issue types they
+	// set stay replaceable under the human type policy across restarts.
+	Logins []string `json:"logins"`
+	// EchoWindow is how long a field write is expected to come back as an
+	// edited event.
+	EchoWindow Duration `json:"echoWindow"`
+	// MaxChainDepth is how many times in a row the automation may react to
+	// an item by writing to it, each within EchoWindow of the one before and
+	// with no edit by a person in between, before further events on the item
+	// are ignored.
+	MaxChainDepth int `json:"maxChainDepth"`
+}
+
+// IsAutomation reports whether login is one of the automation's accounts.
+func (c LoopConfig) IsAutomation(login string) bool {
+	return containsFold(c.Logins, login)
+}
+
+type fieldWrite struct {
+	itemID  string
+	fieldID string
+}
+
+// LoopGuard remembers the project item fields the automation wrote, so the
+// edited events GitHub sends back for them can be told apart from human
+// edits, and counts how often the automation reacts to each item, so a
+// chain of reactions that feeds itself can be stopped.
+type LoopGuard struct {
+	mu           sync.Mutex
+	window       time.Duration
+	writes       map[fieldWrite]time.Time
+	lastWrite    map[string]time.Time
+	lastReaction map[string]time.Time
+	reactions    map[string]int
+}
+
+func NewLoopGuard(window time.Duration) *LoopGuard {
+	return &LoopGuard{
+		window:       window,
+		writes:       make(map[fieldWrite]time.Time),
+		lastWrite:    make(map[string]time.Time),
+		lastReaction: make(map[string]time.Time),
+		reactions:    make(map[string]int),
+	}
+}
+
+// Record notes a write to the item's field at now. It is called before the
+// mutation is sent, as the event can arrive before the response, and only for
+// writes that change the value, as GitHub sends no event otherwise.
+func (g *LoopGuard) Record(itemID, fieldID string, now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.expire(now)
+	g.writes[fieldWrite{itemID, fieldID}] = now
+	g.lastWrite[itemID] = now
+}
+
+// Forget drops the write to the item's field recorded at recordedAt, as when
+// the mutation failed and no event will come back. A later write to the same
+// field is kept.
+func (g *LoopGuard) Forget(itemID, fieldID string, recordedAt time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := fieldWrite{itemID, fieldID}
+	if written, ok := g.writes[key]; ok && written.Equal(recordedAt) {
+		delete(g.writes, key)
+	}
+}
+
+// IsEcho reports whether an edit of the item's field at now is the automation's
+// own write coming back. Each write is matched once.
+func (g *LoopGuard) IsEcho(itemID, fieldID string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := fieldWrite{itemID, fieldID}
+	written, ok := g.writes[key]
+	if !ok || now.Sub(written) > g.window {
+		return false
+	}
+	delete(g.writes, key)
+	return true
+}
+
+// Reacted counts the handling of an event for the item that started at since,
+// if it wrote to the item. Reactions more than a window apart start a new
+// chain.
+func (g *LoopGuard) Reacted(itemID string, since, now time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if written, ok := g.lastWrite[itemID]; !ok || written.Before(since) {
+		return
+	}
+	if last, ok := g.lastReaction[itemID]; !ok || now.Sub(last) > g.window {
+		g.reactions[itemID] = 0
+	}
+	g.reactions[itemID]++
+	g.lastReaction[itemID] = now
+}
+
+// ChainDepth returns how many times in a row the automation has reacted to
+// the item as of now.
+func (g *LoopGuard) ChainDepth(itemID string, now time.Time) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	last, ok := g.lastReaction[itemID]
+	if !ok || now.Sub(last) > g.window {
+		return 0
+	}
+	return g.reactions[itemID]
+}
+
+// ResetChain starts the item's chain again, after an edit that is not one of
+// the automation's own writes.
+func (g *LoopGuard) ResetChain(itemID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.reactions, itemID)
+	delete(g.lastReaction, itemID)
+}
+
+func (g *LoopGuard) expire(now time.Time) {
+	for key, written := range g.writes {
+		if now.Sub(written) > g.window {
+			delete(g.writes, key)
+		}
+	}
+	for itemID, written := range g.lastWrite {
+		if now.Sub(written) > g.window {
+			delete(g.lastWrite, itemID)
+		}
+	}
+	for itemID, reacted := range g.lastReaction {
+		if now.Sub(reacted) > g.window {
+			delete(g.lastReaction, itemID)
+			delete(g.reactions, itemID)
+		}
+	}
+}
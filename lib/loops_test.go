@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoopConfig", func() {
+	It("should recognise the automation's own logins", func() {
+		config := LoopConfig{Logins: []string{"ghproject[bot]"}}
+		Expect(config.IsAutomation("GHProject[bot]")).To(BeTrue())
+		Expect(config.IsAutomation("derik")).To(BeFalse())
+		Expect(LoopConfig{}.IsAutomation("")).To(BeFalse())
+	})
+})
+
+var _ = Describe("LoopGuard", func() {
+	var (
+		guard *LoopGuard
+		start time.Time
+	)
+
+	BeforeEach(func() {
+		guard = NewLoopGuard(time.Minute)
+		start = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	Describe("IsEcho", func() {
+		It("should match a recent write once", func() {
+			guard.Record("item", "start-date", start)
+
+			Expect(guard.IsEcho("item", "start-date", start.Add(time.Second))).To(BeTrue())
+			Expect(guard.IsEcho("item", "start-date", start.Add(2*time.Second))).To(BeFalse())
+		})
+
+		It("should not match other fields, other items or old writes", func() {
+			guard.Record("item", "start-date", start)
+
+			Expect(guard.IsEcho("item", "status", start)).To(BeFalse())
+			Expect(guard.IsEcho("other", "start-date", start)).To(BeFalse())
+			Expect(guard.IsEcho("item", "start-date", start.Add(2*time.Minute))).To(BeFalse())
+		})
+	})
+
+	Describe("Forget", func() {
+		It("should drop the write it was recorded for and keep later ones", func() {
+			guard.Record("item", "status", start)
+			guard.Forget("item", "status", start)
+			Expect(guard.IsEcho("item", "status", start.Add(time.Second))).To(BeFalse())
+
+			guard.Record("item", "status", start.Add(time.Second))
+			guard.Forget("item", "status", start)
+			Expect(guard.IsEcho("item", "status", start.Add(2*time.Second))).To(BeTrue())
+		})
+	})
+
+	Describe("ChainDepth", func() {
+		It("should count reactions that wrote to the item", func() {
+			Expect(guard.ChainDepth("item", start)).To(Equal(0))
+
+			guard.Reacted("item", start, start)
+			Expect(guard.ChainDepth("item", start)).To(Equal(0), "nothing was written")
+
+			guard.Record("item", "status", start.Add(time.Second))
+			guard.Reacted("item", start, start.Add(time.Second))
+			guard.Record("item", "end-date", start.Add(2*time.Second))
+			guard.Reacted("item", start.Add(2*time.Second), start.Add(2*time.Second))
+			Expect(guard.ChainDepth("item", start.Add(3*time.Second))).To(Equal(2))
+		})
+
+		It("should let a person edit the item after several echoes", func() {
+			guard.Record("item", "status", start)
+			guard.Record("item", "end-date", start)
+			guard.Record("item", "duration", start)
+			guard.Record("item", "rank", start)
+			guard.Reacted("item", start, start)
+			for _, field := range []string{"status", "end-date", "duration", "rank"} {
+				Expect(guard.IsEcho("item", field, start.Add(time.Second))).To(BeTrue())
+			}
+
+			Expect(guard.ChainDepth("item", start.Add(2*time.Second))).To(Equal(1))
+			guard.ResetChain("item")
+			Expect(guard.ChainDepth("item", start.Add(2*time.Second))).To(Equal(0))
+		})
+
+		It("should start again once the item is quiet", func() {
+			guard.Record("item", "status", start)
+			guard.Reacted("item", start, start)
+			Expect(guard.ChainDepth("item", start.Add(time.Second))).To(Equal(1))
+			Expect(guard.ChainDepth("item", start.Add(5*time.Minute))).To(Equal(0))
+
+			guard.Record("item", "status", start.Add(5*time.Minute))
+			guard.Reacted("item", start.Add(5*time.Minute), start.Add(5*time.Minute))
+			Expect(guard.ChainDepth("item", start.Add(5*time.Minute+time.Second))).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,172 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	MatcherConventional = "conventional"
+	MatcherBracket      = "bracket"
+	MatcherRegex        = "regex"
+	MatcherEmoji        = "emoji"
+)
+
+// TitleMatch is what a TitleMatcher found in a title. Tag is the marker as
+// written, such as "feat" or "Bug", and is resolved to Type by the
+// TypeMapping unless the matcher already knows the type.
+type TitleMatch struct {
+	Tag      string
+	Type     string
+	Scope    string
+	Breaking bool
+}
+
+type TitleMatcher interface {
+	Match(title string) (TitleMatch, bool)
+}
+
+// MatcherConfig configures one title matcher. Pattern is used by regex
+// matchers and must have a "type" named group, and may have "scope" and
+// "breaking" groups. Emoji maps a leading emoji or :shortcode: to a type name.
+type MatcherConfig struct {
+	Kind    string            `json:"kind"`
+	Pattern string            `json:"pattern,omitempty"`
+	Emoji   map[string]string `json:"emoji,omitempty"`
+}
+
+func DefaultMatchers() []MatcherConfig {
+	return []MatcherConfig{
+		{Kind: MatcherConventional},
+		{Kind: MatcherBracket},
+	}
+}
+
+func NewTitleMatcher(config MatcherConfig) (TitleMatcher, error) {
+	switch config.Kind {
+	case MatcherConventional:
+		return ConventionalMatcher{}, nil
+	case MatcherBracket:
+		return BracketMatcher{}, nil
+	case MatcherRegex:
+		return NewRegexMatcher(config.Pattern)
+	case MatcherEmoji:
+		if len(config.Emoji) == 0 {
+			return nil, fmt.Errorf("emoji matcher needs an emoji table")
+		}
+		return EmojiMatcher(config.Emoji), nil
+	}
+	return nil, fmt.Errorf("unknown matcher kind %q", config.Kind)
+}
+
+// ConventionalMatcher matches "prefix(scope)!: text" titles.
+type ConventionalMatcher struct{}
+
+func (ConventionalMatcher) Match(title string) (TitleMatch, bool) {
+	colonIndex := strings.Index(title, ":")
+	if colonIndex == -1 {
+		return TitleMatch{}, false
+	}
+
+	var match TitleMatch
+	prefix := strings.TrimSpace(title[:colonIndex])
+
+	if strings.HasSuffix(prefix, "!") {
+		match.Breaking = true
+		prefix = strings.TrimSpace(strings.TrimSuffix(prefix, "!"))
+	}
+
+	if parenIndex := strings.Index(prefix, "("); parenIndex != -1 {
+		if closeIndex := strings.Index(prefix[parenIndex:], ")"); closeIndex != -1 {
+			match.Scope = strings.TrimSpace(prefix[parenIndex+1 : parenIndex+closeIndex])
+		}
+		prefix = strings.TrimSpace(prefix[:parenIndex])
+	}
+
+	if prefix == "" {
+		return TitleMatch{}, false
+	}
+	match.Tag = prefix
+	return match, true
+}
+
+var bracketPattern = regexp.MustCompile(`^\s*\[([^\]]+)\]`)
+
+// BracketMatcher matches "[Tag] text" titles.
+type BracketMatcher struct{}
+
+func (BracketMatcher) Match(title string) (TitleMatch, bool) {
+	groups := bracketPattern.FindStringSubmatch(title)
+	if groups == nil {
+		return TitleMatch{}, false
+	}
+	return TitleMatch{Tag: strings.TrimSpace(groups[1])}, true
+}
+
+type RegexMatcher struct {
+	pattern *regexp.Regexp
+}
+
+func NewRegexMatcher(pattern string) (*RegexMatcher, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling matcher pattern %q: %w", pattern, err)
+	}
+	if compiled.SubexpIndex("type") == -1 {
+		return nil, fmt.Errorf("matcher pattern %q has no \"type\" group", pattern)
+	}
+	return &RegexMatcher{pattern: compiled}, nil
+}
+
+func (m *RegexMatcher) Match(title string) (TitleMatch, bool) {
+	groups := m.pattern.FindStringSubmatch(title)
+	if groups == nil {
+		return TitleMatch{}, false
+	}
+
+	group := func(name string) string {
+		if index := m.pattern.SubexpIndex(name); index != -1 {
+			return strings.TrimSpace(groups[index])
+		}
+		return ""
+	}
+	return TitleMatch{
+		Tag:      group("type"),
+		Scope:    group("scope"),
+		Breaking: group("breaking") != "",
+	}, group("type") != ""
+}
+
+// EmojiMatcher maps a leading emoji or gitmoji shortcode straight to a type.
+// The longest matching key wins, so variants with a selector can be listed
+// next to the bare emoji.
+type EmojiMatcher map[string]string
+
+func (m EmojiMatcher) Match(title string) (TitleMatch, bool) {
+	title = strings.TrimSpace(title)
+	var match TitleMatch
+	for emoji, typeName := range m {
+		if strings.HasPrefix(title, emoji) && len(emoji) > len(match.Tag) {
+			match = TitleMatch{Tag: emoji, Type: typeName}
+		}
+	}
+	return match, match.Tag != ""
+}
+
+var breakingFooterPattern = regexp.MustCompile(`(?m)^\s*BREAKING[ -]CHANGE\s*:`)
+
+// HasBreakingChangeFooter reports whether body has a conventional commit
+// "BREAKING CHANGE:" or "BREAKING-CHANGE:" footer.
+func HasBreakingChangeFooter(body string) bool {
+	return breakingFooterPattern.MatchString(body)
+}
+
+// IsBreakingChange reports whether the title marks a breaking change with
+// "!" or the body has a breaking change footer.
+func (tm *TypeMapping) IsBreakingChange(title, body string) bool {
+	if match, found := tm.Match(title); found && match.Breaking {
+		return true
+	}
+	return HasBreakingChangeFooter(body)
+}
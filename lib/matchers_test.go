@@ -0,0 +1,155 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Title matchers", func() {
+	Describe("ConventionalMatcher", func() {
+		It("should extract the tag, scope and breaking flag", func() {
+			testCases := map[string]TitleMatch{
+				"feat: add":              {Tag: "feat"},
+				"feat(api): add":         {Tag: "feat", Scope: "api"},
+				"feat(api)(v2): add":     {Tag: "feat", Scope: "api"},
+				"feat!: drop v1":         {Tag: "feat", Breaking: true},
+				"feat(api)!: drop v1":    {Tag: "feat", Scope: "api", Breaking: true},
+				" fix(ske-operator) : x": {Tag: "fix", Scope: "ske-operator"},
+			}
+
+			for title, expected := range testCases {
+				match, found := ConventionalMatcher{}.Match(title)
+				Expect(found).To(BeTrue(), "Expected a match for title: %s", title)
+				Expect(match).To(Equal(expected), "Unexpected match for title: %s", title)
+			}
+		})
+
+		It("should not match titles without a prefix", func() {
+			for _, title := range []string{"add new feature", ": no prefix", "!: nothing"} {
+				_, found := ConventionalMatcher{}.Match(title)
+				Expect(found).To(BeFalse(), "Expected no match for title: %s", title)
+			}
+		})
+	})
+
+	Describe("BracketMatcher", func() {
+		It("should extract the tag in leading brackets", func() {
+			match, found := BracketMatcher{}.Match(" [Bug] login fails")
+			Expect(found).To(BeTrue())
+			Expect(match.Tag).To(Equal("Bug"))
+
+			_, found = BracketMatcher{}.Match("login fails [Bug]")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("RegexMatcher", func() {
+		It("should use the named groups", func() {
+			matcher, err := NewRegexMatcher(`^(?P<type>\w+)(?P<breaking>!)?\s+-\s+`)
+			Expect(err).NotTo(HaveOccurred())
+
+			match, found := matcher.Match("Bug - login fails")
+			Expect(found).To(BeTrue())
+			Expect(match).To(Equal(TitleMatch{Tag: "Bug"}))
+
+			match, found = matcher.Match("Feat! - new API")
+			Expect(found).To(BeTrue())
+			Expect(match).To(Equal(TitleMatch{Tag: "Feat", Breaking: true}))
+		})
+
+		It("should require a type group", func() {
+			_, err := NewRegexMatcher(`^(?P<kind>\w+):`)
+			Expect(err).To(MatchError(ContainSubstring(`no "type" group`)))
+		})
+	})
+
+	Describe("EmojiMatcher", func() {
+		It("should map the leading emoji to a type", func() {
+			matcher := EmojiMatcher{"🐛": "Bug", ":bug:": "Bug", "✨": "Feature"}
+
+			match, found := matcher.Match("🐛 login fails")
+			Expect(found).To(BeTrue())
+			Expect(match.Type).To(Equal("Bug"))
+
+			match, found = matcher.Match(":bug: login fails")
+			Expect(found).To(BeTrue())
+			Expect(match.Type).To(Equal("Bug"))
+
+			_, found = matcher.Match("login fails 🐛")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("TypeMapping.Match", func() {
+		var typeMapping *TypeMapping
+
+		BeforeEach(func() {
+			var err error
+			typeMapping, err = NewTypeMappingFromConfig(IssueTypesConfig{
+				Prefixes: DefaultTypePrefixes(),
+				Matchers: []MatcherConfig{
+					{Kind: MatcherEmoji, Emoji: map[string]string{"🐛": "Bug"}},
+					{Kind: MatcherConventional},
+					{Kind: MatcherBracket},
+					{Kind: MatcherRegex, Pattern: `^(?P<type>\w+)\s+-\s+`},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return the first match that resolves to a type", func() {
+			testCases := map[string]TitleMatch{
+				"🐛 login fails":           {Tag: "🐛", Type: "Bug"},
+				"feat(api)!: new API":     {Tag: "feat", Type: "Feature", Scope: "api", Breaking: true},
+				"[Bug] login fails":       {Tag: "Bug", Type: "Bug"},
+				"[docs] fix typo":         {Tag: "docs", Type: "Docs"},
+				"Spike - try new storage": {Tag: "Spike", Type: "Spike"},
+			}
+
+			for title, expected := range testCases {
+				match, found := typeMapping.Match(title)
+				Expect(found).To(BeTrue(), "Expected a match for title: %s", title)
+				Expect(match).To(Equal(expected), "Unexpected match for title: %s", title)
+			}
+		})
+
+		It("should fall through matchers whose tag is unknown", func() {
+			match, found := typeMapping.Match("[Bug]: flaky test")
+			Expect(found).To(BeTrue())
+			Expect(match.Type).To(Equal("Bug"))
+
+			_, found = typeMapping.Match("[WIP] nothing to see")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should reject invalid matcher configuration", func() {
+			_, err := NewTypeMappingFromConfig(IssueTypesConfig{Matchers: []MatcherConfig{{Kind: "magic"}}})
+			Expect(err).To(MatchError(ContainSubstring("unknown matcher kind")))
+			_, err = NewTypeMappingFromConfig(IssueTypesConfig{Matchers: []MatcherConfig{{Kind: MatcherRegex, Pattern: "("}}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Breaking changes", func() {
+		It("should detect breaking change footers in the body", func() {
+			Expect(HasBreakingChangeFooter("Drops v1.\n\nBREAKING CHANGE: the v1 API is gone")).To(BeTrue())
+			Expect(HasBreakingChangeFooter("BREAKING-CHANGE: renamed flag")).To(BeTrue())
+			Expect(HasBreakingChangeFooter("This is not a BREAKING CHANGE: honest")).To(BeFalse())
+			Expect(HasBreakingChangeFooter("")).To(BeFalse())
+		})
+
+		It("should detect breaking changes from the title or the body", func() {
+			typeMapping := NewTypeMapping()
+			Expect(typeMapping.IsBreakingChange("feat!: drop v1", "")).To(BeTrue())
+			Expect(typeMapping.IsBreakingChange("feat(api)!: drop v1", "")).To(BeTrue())
+			Expect(typeMapping.IsBreakingChange("feat: drop v1", "BREAKING CHANGE: v1 is gone")).To(BeTrue())
+			Expect(typeMapping.IsBreakingChange("feat: add v2", "Adds v2.")).To(BeFalse())
+		})
+
+		It("should recognise the type of titles marked with an exclamation mark", func() {
+			typeName, found := NewTypeMapping().GetTypeFromTitle("feat!: drop v1")
+			Expect(found).To(BeTrue())
+			Expect(typeName).To(Equal("Feature"))
+		})
+	})
+})
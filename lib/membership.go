@@ -0,0 +1,129 @@
+package lib
+
+import (
+	"sync"
+	"time"
+)
+
+// Membership caches which issues and pull requests are on a project, keyed
+// by content node ID, so adding an item that is already there costs no
+// mutation.
+type Membership struct {
+	mu    sync.Mutex
+	items map[string]string
+	// changed is when each content was last added or removed, so a listing
+	// that started earlier does not undo the change.
+	changed map[string]time.Time
+	// pending counts the additions sent for each content that have not
+	// returned yet.
+	pending map[string]int
+	saved   int
+}
+
+func NewMembership() *Membership {
+	return &Membership{
+		items:   make(map[string]string),
+		changed: make(map[string]time.Time),
+		pending: make(map[string]int),
+	}
+}
+
+// Replace swaps the cache for a full listing of the project's items that
+// started at listedAt, keeping the additions and removals made since.
+func (m *Membership) Replace(items map[string]string, listedAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for contentID, changedAt := range m.changed {
+		if !changedAt.After(listedAt) {
+			delete(m.changed, contentID)
+			continue
+		}
+		if itemID, ok := m.items[contentID]; ok {
+			items[contentID] = itemID
+		} else {
+			delete(items, contentID)
+		}
+	}
+	m.items = items
+}
+
+// BeginAdd marks an addition of the content as sent, before the mutation
+// goes out, as its created event can arrive before the response. Finish it
+// with Add or CancelAdd.
+func (m *Membership) BeginAdd(contentID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending[contentID]++
+}
+
+// CancelAdd ends an addition started with BeginAdd that failed.
+func (m *Membership) CancelAdd(contentID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endAdd(contentID)
+}
+
+// Add records the content's item, ending an addition started with BeginAdd.
+func (m *Membership) Add(contentID, itemID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[contentID] = itemID
+	m.changed[contentID] = time.Now()
+	m.endAdd(contentID)
+}
+
+func (m *Membership) endAdd(contentID string) {
+	if m.pending[contentID] <= 1 {
+		delete(m.pending, contentID)
+		return
+	}
+	m.pending[contentID]--
+}
+
+// AddedByAutomation reports whether the item was, or is being, added by the
+// automation.
+func (m *Membership) AddedByAutomation(contentID, itemID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pending[contentID] > 0 || m.items[contentID] == itemID
+}
+
+// Remove forgets the item with itemID, which is all a deletion or archive
+// event is guaranteed to identify.
+func (m *Membership) Remove(itemID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for contentID, cached := range m.items {
+		if cached == itemID {
+			delete(m.items, contentID)
+			m.changed[contentID] = time.Now()
+		}
+	}
+}
+
+func (m *Membership) ItemID(contentID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	itemID, ok := m.items[contentID]
+	return itemID, ok
+}
+
+// RecordSaved counts an item addition answered from the cache.
+func (m *Membership) RecordSaved() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved++
+}
+
+func (m *Membership) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.items)
+}
+
+// Saved returns how many mutations RecordSaved counted.
+func (m *Membership) Saved() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saved
+}
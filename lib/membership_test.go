@@ -0,0 +1,83 @@
+package lib
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Membership", func() {
+	var membership *Membership
+
+	BeforeEach(func() {
+		membership = NewMembership()
+		membership.Replace(map[string]string{"issue-1": "item-1", "pr-2": "item-2"}, time.Now())
+	})
+
+	It("should answer cached items", func() {
+		itemID, ok := membership.ItemID("issue-1")
+		Expect(ok).To(BeTrue())
+		Expect(itemID).To(Equal("item-1"))
+
+		_, ok = membership.ItemID("issue-3")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should count the saved mutations", func() {
+		membership.RecordSaved()
+		membership.RecordSaved()
+		Expect(membership.Saved()).To(Equal(2))
+	})
+
+	It("should track added and removed items", func() {
+		membership.Add("issue-3", "item-3")
+		membership.Remove("item-1")
+
+		Expect(membership.Len()).To(Equal(2))
+		_, ok := membership.ItemID("issue-1")
+		Expect(ok).To(BeFalse())
+		itemID, _ := membership.ItemID("issue-3")
+		Expect(itemID).To(Equal("item-3"))
+	})
+
+	It("should keep changes made while the listing was running", func() {
+		listedAt := time.Now().Add(-time.Second)
+		membership.Add("issue-3", "item-3")
+		membership.Remove("item-2")
+
+		membership.Replace(map[string]string{"issue-1": "item-1", "pr-2": "item-2", "issue-4": "item-4"}, listedAt)
+
+		Expect(membership.Len()).To(Equal(3))
+		_, ok := membership.ItemID("pr-2")
+		Expect(ok).To(BeFalse())
+		itemID, _ := membership.ItemID("issue-3")
+		Expect(itemID).To(Equal("item-3"))
+	})
+
+	It("should let later listings drop what they no longer have", func() {
+		membership.Add("issue-3", "item-3")
+
+		membership.Replace(map[string]string{"issue-1": "item-1"}, time.Now())
+
+		_, ok := membership.ItemID("issue-3")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should recognise its own additions while they are in flight", func() {
+		membership.BeginAdd("issue-3")
+		Expect(membership.AddedByAutomation("issue-3", "item-3")).To(BeTrue())
+
+		membership.Add("issue-3", "item-3")
+		Expect(membership.AddedByAutomation("issue-3", "item-3")).To(BeTrue())
+		Expect(membership.AddedByAutomation("issue-3", "item-9")).To(BeFalse(), "the content was added again by someone else")
+	})
+
+	It("should not claim additions that failed", func() {
+		membership.BeginAdd("issue-3")
+		membership.CancelAdd("issue-3")
+
+		Expect(membership.AddedByAutomation("issue-3", "item-3")).To(BeFalse())
+		Expect(membership.AddedByAutomation("issue-1", "item-1")).To(BeTrue())
+	})
+})
@@ -0,0 +1,88 @@
+package lib
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// RankingConfig keeps the board order visible outside the board view. Items
+// are ranked from 1 within their status column.
+type RankingConfig struct {
+	// RankField is a number field set to the item's rank.
+	RankField string `json:"rankField"`
+	// PriorityField is a single select field set from PriorityBands.
+	PriorityField string         `json:"priorityField"`
+	PriorityBands []PriorityBand `json:"priorityBands"`
+}
+
+// PriorityBand gives the next Size items of a column the Option. A Size of
+// zero takes the rest of the column.
+type PriorityBand struct {
+	Option string `json:"option"`
+	Size   int    `json:"size"`
+}
+
+// RankUpdate is a field value that no longer matches the item's position.
+type RankUpdate struct {
+	ItemID string
+	Field  string
+	Value  string
+}
+
+// Enabled reports whether reorder events need handling at all.
+func (r RankingConfig) Enabled() bool {
+	return r.RankField != "" || (r.PriorityField != "" && len(r.PriorityBands) > 0)
+}
+
+// RankUpdates returns the rank and priority values that differ from what the
+// items, given in board order, already have. Items are grouped by the value
+// of statusField, and only the given columns are ranked, or every column when
+// none are given.
+func (r RankingConfig) RankUpdates(statusField string, items []ProjectItem, columns []string) []RankUpdate {
+	var updates []RankUpdate
+	positions := make(map[string]int)
+	for _, item := range items {
+		status := item.Values[statusField]
+		if len(columns) > 0 && !slices.Contains(columns, status) {
+			continue
+		}
+		positions[status]++
+		rank := positions[status]
+
+		if r.RankField != "" {
+			value := strconv.Itoa(rank)
+			if item.Values[r.RankField] != value {
+				updates = append(updates, RankUpdate{ItemID: item.ID, Field: r.RankField, Value: value})
+			}
+		}
+		if r.PriorityField != "" {
+			if option, ok := r.band(rank); ok && item.Values[r.PriorityField] != option {
+				updates = append(updates, RankUpdate{ItemID: item.ID, Field: r.PriorityField, Value: option})
+			}
+		}
+	}
+	return updates
+}
+
+func (r RankingConfig) band(rank int) (string, bool) {
+	for _, band := range r.PriorityBands {
+		if band.Size == 0 || rank <= band.Size {
+			return band.Option, true
+		}
+		rank -= band.Size
+	}
+	return "", false
+}
+
+func (r RankingConfig) validate() error {
+	for i, band := range r.PriorityBands {
+		if band.Size < 0 {
+			return fmt.Errorf("priority band %q has a negative size", band.Option)
+		}
+		if band.Size == 0 && i != len(r.PriorityBands)-1 {
+			return fmt.Errorf("only the last priority band may take the rest of the column")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RankingConfig", func() {
+	items := []ProjectItem{
+		{ID: "a", Values: map[string]string{"Status": "Todo", "Rank": "1"}},
+		{ID: "b", Values: map[string]string{"Status": "In progress"}},
+		{ID: "c", Values: map[string]string{"Status": "Todo", "Rank": "3", "Priority": "P1"}},
+		{ID: "d", Values: map[string]string{"Status": "Todo", "Rank": "2"}},
+	}
+
+	It("should rank items within their status column", func() {
+		ranking := RankingConfig{RankField: "Rank"}
+
+		Expect(ranking.RankUpdates("Status", items, nil)).To(Equal([]RankUpdate{
+			{ItemID: "b", Field: "Rank", Value: "1"},
+			{ItemID: "c", Field: "Rank", Value: "2"},
+			{ItemID: "d", Field: "Rank", Value: "3"},
+		}))
+	})
+
+	It("should derive priority bands from the rank", func() {
+		ranking := RankingConfig{
+			PriorityField: "Priority",
+			PriorityBands: []PriorityBand{{Option: "P0", Size: 1}, {Option: "P1", Size: 1}, {Option: "P2"}},
+		}
+
+		Expect(ranking.RankUpdates("Status", items, nil)).To(Equal([]RankUpdate{
+			{ItemID: "a", Field: "Priority", Value: "P0"},
+			{ItemID: "b", Field: "Priority", Value: "P0"},
+			{ItemID: "d", Field: "Priority", Value: "P2"},
+		}))
+	})
+
+	It("should leave items past the last band alone", func() {
+		ranking := RankingConfig{PriorityField: "Priority", PriorityBands: []PriorityBand{{Option: "P0", Size: 1}}}
+
+		Expect(ranking.RankUpdates("Status", items, nil)).To(HaveLen(2))
+	})
+
+	It("should only rank the given columns", func() {
+		ranking := RankingConfig{RankField: "Rank"}
+
+		Expect(ranking.RankUpdates("Status", items, []string{"In progress"})).To(Equal([]RankUpdate{
+			{ItemID: "b", Field: "Rank", Value: "1"},
+		}))
+	})
+
+	It("should only let the last band take the rest of the column", func() {
+		Expect(RankingConfig{PriorityBands: []PriorityBand{{Option: "P0", Size: 2}, {Option: "P1"}}}.validate()).To(Succeed())
+		Expect(RankingConfig{PriorityBands: []PriorityBand{{Option: "P0"}, {Option: "P1", Size: 2}}}.validate()).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,63 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// DefaultIssueBranchPattern matches branches that start with the issue
+// number, optionally after "issue-" or "gh-" and one of the usual type
+// directories, such as "123-fix-login", "gh-123" or "feature/123-fix-login".
+// Other directories, such as "release/2024-x", do not name an issue.
+const DefaultIssueBranchPattern = `(?i)^(?:(?:feature|feat|fix|bug|bugfix|hotfix|chore|docs|spike)/)?(?:issue-|gh-)?(?P<issue>\d+)(?:[-_]|$)`
+
+var issueReferencePattern = regexp.MustCompile(`(?:^|[^\w/#])#(\d+)\b`)
+
+// BranchConvention finds the issue a branch is named after.
+type BranchConvention struct {
+	pattern *regexp.Regexp
+}
+
+// NewBranchConvention compiles pattern, which must have an "issue" named
+// group for the issue number. An empty pattern uses
+// DefaultIssueBranchPattern.
+func NewBranchConvention(pattern string) (*BranchConvention, error) {
+	if pattern == "" {
+		pattern = DefaultIssueBranchPattern
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling issue branch pattern %q: %w", pattern, err)
+	}
+	if compiled.SubexpIndex("issue") == -1 {
+		return nil, fmt.Errorf("issue branch pattern %q has no \"issue\" group", pattern)
+	}
+	return &BranchConvention{pattern: compiled}, nil
+}
+
+// IssueNumber returns the number of the issue branch is named after.
+func (c *BranchConvention) IssueNumber(branch string) (int, bool) {
+	groups := c.pattern.FindStringSubmatch(branch)
+	if groups == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(groups[c.pattern.SubexpIndex("issue")])
+	return number, err == nil && number > 0
+}
+
+// ReferencedIssueNumbers returns the numbers of the same-repository issues
+// referenced as "#123" in text, including "Fixes #123", in order of first
+// appearance.
+func ReferencedIssueNumbers(text string) []int {
+	var numbers []int
+	for _, groups := range issueReferencePattern.FindAllStringSubmatch(text, -1) {
+		number, err := strconv.Atoi(groups[1])
+		if err != nil || number == 0 || slices.Contains(numbers, number) {
+			continue
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
@@ -0,0 +1,70 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Issue references", func() {
+	Describe("BranchConvention", func() {
+		var convention *BranchConvention
+
+		BeforeEach(func() {
+			var err error
+			convention, err = NewBranchConvention("")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should find the issue number at the start of the branch name", func() {
+			testCases := map[string]int{
+				"123-fix-login":         123,
+				"123_fix_login":         123,
+				"123":                   123,
+				"gh-45-flaky-test":      45,
+				"issue-7":               7,
+				"feature/123-fix-login": 123,
+				"fix/9_typo":            9,
+			}
+
+			for branch, expected := range testCases {
+				number, found := convention.IssueNumber(branch)
+				Expect(found).To(BeTrue(), "Expected an issue number in branch: %s", branch)
+				Expect(number).To(Equal(expected), "Unexpected issue number in branch: %s", branch)
+			}
+		})
+
+		It("should ignore branches without a leading number", func() {
+			for _, branch := range []string{"main", "fix-login-123", "v1.2.3", "release-2024", "release/2024-x", "derik/88-new-api", "0-nothing"} {
+				_, found := convention.IssueNumber(branch)
+				Expect(found).To(BeFalse(), "Expected no issue number in branch: %s", branch)
+			}
+		})
+
+		It("should follow a configured convention", func() {
+			convention, err := NewBranchConvention(`^[a-z]+/(?P<issue>\d+)-`)
+			Expect(err).NotTo(HaveOccurred())
+
+			number, found := convention.IssueNumber("derik/88-new-api")
+			Expect(found).To(BeTrue())
+			Expect(number).To(Equal(88))
+			_, found = convention.IssueNumber("88-new-api")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should require an issue group", func() {
+			_, err := NewBranchConvention(`^(\d+)-`)
+			Expect(err).To(MatchError(ContainSubstring(`"issue" group`)))
+		})
+	})
+
+	Describe("ReferencedIssueNumbers", func() {
+		It("should find plain and closing references", func() {
+			Expect(ReferencedIssueNumbers("Fixes #123, relates to #45.\n\nSee #123 again")).To(Equal([]int{123, 45}))
+			Expect(ReferencedIssueNumbers("#9 at the start")).To(Equal([]int{9}))
+		})
+
+		It("should ignore references to other repositories and anchors", func() {
+			Expect(ReferencedIssueNumbers("syntasso/kratix#12 and docs/page#3 and a##4")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type CheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+type CheckRun struct {
+	Name       string         `json:"name"`
+	HeadSHA    string         `json:"head_sha"`
+	Status     string         `json:"status,omitempty"`
+	Conclusion string         `json:"conclusion,omitempty"`
+	Output     CheckRunOutput `json:"output"`
+}
+
+type CommitStatus struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// CreateCheckRun creates a completed check run on a commit. The Checks API
+// only accepts GitHub App tokens; use CreateCommitStatus with a personal
+// access token.
+func (g *GithubClient) CreateCheckRun(owner, repository string, run CheckRun) error {
+	path := fmt.Sprintf("/repos/%s/%s/check-runs", owner, repository)
+	return g.doREST(http.MethodPost, path, run)
+}
+
+func (g *GithubClient) CreateCommitStatus(owner, repository, sha string, status CommitStatus) error {
+	path := fmt.Sprintf("/repos/%s/%s/statuses/%s", owner, repository, sha)
+	return g.doREST(http.MethodPost, path, status)
+}
+
+// doREST sends body as JSON to the REST API for the endpoints GraphQL does
+// not cover.
+func (g *GithubClient) doREST(method, path string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(g.ctx, method, g.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s: %v body: %q", method, path, resp.Status, respBody)
+	}
+	return nil
+}
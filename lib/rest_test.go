@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("REST API", func() {
+	var (
+		server   *httptest.Server
+		client   *GithubClient
+		path     string
+		received map[string]interface{}
+		status   int
+	)
+
+	BeforeEach(func() {
+		status = http.StatusCreated
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.Method + " " + r.URL.Path
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message": "nope"}`))
+		}))
+		client = newGithubClient(server.Client(), server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should create check runs", func() {
+		err := client.CreateCheckRun("syntasso", "kratix", CheckRun{
+			Name:       "PR title",
+			HeadSHA:    "abc123",
+			Status:     "completed",
+			Conclusion: "failure",
+			Output:     CheckRunOutput{Title: "bad", Summary: "use a prefix"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal("POST /repos/syntasso/kratix/check-runs"))
+		Expect(received).To(HaveKeyWithValue("head_sha", "abc123"))
+		Expect(received).To(HaveKeyWithValue("conclusion", "failure"))
+	})
+
+	It("should create commit statuses", func() {
+		err := client.CreateCommitStatus("syntasso", "kratix", "abc123", CommitStatus{State: "success", Context: "PR title"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal("POST /repos/syntasso/kratix/statuses/abc123"))
+		Expect(received).To(HaveKeyWithValue("state", "success"))
+	})
+
+	It("should return API errors", func() {
+		status = http.StatusForbidden
+		err := client.CreateCommitStatus("syntasso", "kratix", "abc123", CommitStatus{State: "success"})
+		Expect(err).To(MatchError(ContainSubstring("403")))
+	})
+})
@@ -0,0 +1,140 @@
+package lib
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	FieldTypeDate         = "DATE"
+	FieldTypeNumber       = "NUMBER"
+	FieldTypeText         = "TEXT"
+	FieldTypeSingleSelect = "SINGLE_SELECT"
+)
+
+// SchemaProblem is a field or option referenced by the configuration that the
+// project does not have, or has with the wrong type.
+type SchemaProblem struct {
+	Field   string `json:"field"`
+	Option  string `json:"option,omitempty"`
+	Problem string `json:"problem"`
+}
+
+func (p SchemaProblem) String() string {
+	if p.Option != "" {
+		return fmt.Sprintf("%s option %q: %s", p.Field, p.Option, p.Problem)
+	}
+	return fmt.Sprintf("%s: %s", p.Field, p.Problem)
+}
+
+type schemaValidator struct {
+	details  *ProjectDetails
+	problems []SchemaProblem
+}
+
+// ValidateSchema checks that every field and option the configuration refers
+// to exists in the project with the type the automation expects.
+func (p *ProjectConfig) ValidateSchema(details *ProjectDetails) []SchemaProblem {
+	v := &schemaValidator{details: details}
+
+	v.options(p.StatusField, p.Statuses...)
+	for _, status := range []string{
+		p.PullRequestStatuses.Started,
+		p.PullRequestStatuses.Draft,
+		p.PullRequestStatuses.InReview,
+		p.PullRequestStatuses.Merged,
+		p.PullRequestStatuses.Closed,
+		p.IssueStatuses.Completed,
+		p.IssueStatuses.NotPlanned,
+		p.IssueStatuses.Reopened,
+	} {
+		if status != "" {
+			v.options(p.StatusField, status)
+		}
+	}
+	for _, mapping := range p.DateFields {
+		v.options(p.StatusField, mapping.Status)
+		v.field(mapping.Field, FieldTypeDate)
+	}
+	for _, duration := range p.Durations {
+		v.field(duration.Start, FieldTypeDate)
+		v.field(duration.End, FieldTypeDate)
+		v.field(duration.Field, FieldTypeNumber)
+	}
+	for _, name := range p.Transitions.ClearFields {
+		v.field(name, "")
+	}
+	v.options(p.StatusField, p.Transitions.ReopenFrom...)
+	if p.Transitions.ReopenCountField != "" {
+		v.field(p.Transitions.ReopenCountField, FieldTypeNumber)
+	}
+	if p.AreaField != "" {
+		v.options(p.AreaField, slices.Sorted(maps.Values(p.ScopeAliases))...)
+	}
+	if p.BreakingChangeField != "" {
+		v.options(p.BreakingChangeField, p.BreakingChangeOption)
+	}
+	if p.Ranking.RankField != "" {
+		v.field(p.Ranking.RankField, FieldTypeNumber)
+	}
+	if p.Ranking.PriorityField != "" {
+		for _, band := range p.Ranking.PriorityBands {
+			v.options(p.Ranking.PriorityField, band.Option)
+		}
+	}
+	if details.TypeMapping != nil {
+		if p.TypeField != "" {
+			v.options(p.TypeField, details.TypeMapping.TypeNames()...)
+		}
+		for _, typeName := range details.TypeMapping.UnknownTypes() {
+			v.add(SchemaProblem{Field: "Issue type", Option: typeName, Problem: "not an issue type of the organization"})
+		}
+	}
+
+	return v.problems
+}
+
+func (v *schemaValidator) add(problem SchemaProblem) {
+	for _, existing := range v.problems {
+		if existing == problem {
+			return
+		}
+	}
+	v.problems = append(v.problems, problem)
+}
+
+// field checks that name exists and, when dataType is set, has that type.
+func (v *schemaValidator) field(name, dataType string) {
+	switch field := v.details.FieldsByName[name].(type) {
+	case Field:
+		if dataType != "" && field.DataType != dataType {
+			v.add(SchemaProblem{Field: name, Problem: fmt.Sprintf("expected a %s field, found %s", typeName(dataType), typeName(field.DataType))})
+		}
+	case SingleSelectField:
+		if dataType != "" && dataType != FieldTypeSingleSelect {
+			v.add(SchemaProblem{Field: name, Problem: fmt.Sprintf("expected a %s field, found single select", typeName(dataType))})
+		}
+	default:
+		v.add(SchemaProblem{Field: name, Problem: "field not found"})
+	}
+}
+
+// options checks that name is a single select field with every option listed.
+func (v *schemaValidator) options(name string, options ...string) {
+	field, ok := v.details.FieldsByName[name].(SingleSelectField)
+	if !ok {
+		v.field(name, FieldTypeSingleSelect)
+		return
+	}
+	for _, option := range options {
+		if _, ok := field.OptionByName(option); !ok {
+			v.add(SchemaProblem{Field: name, Option: option, Problem: "option not found"})
+		}
+	}
+}
+
+func typeName(dataType string) string {
+	return strings.ReplaceAll(strings.ToLower(dataType), "_", " ")
+}
@@ -0,0 +1,80 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateSchema", func() {
+	var (
+		config  ProjectConfig
+		details *ProjectDetails
+	)
+
+	addField := func(field interface{}) {
+		switch f := field.(type) {
+		case Field:
+			details.FieldsByName[f.Name] = f
+			details.FieldsByID[f.ID] = f
+		case SingleSelectField:
+			details.FieldsByName[f.Name] = f
+			details.FieldsByID[f.ID] = f
+		}
+	}
+
+	BeforeEach(func() {
+		config = DefaultProjectConfig(1)
+		details = &ProjectDetails{
+			FieldsByID:   map[string]interface{}{},
+			FieldsByName: map[string]interface{}{},
+		}
+		addField(SingleSelectField{ID: "status", Name: "Status", Options: map[string]Field{
+			"o1": {ID: "o1", Name: "Todo"},
+			"o2": {ID: "o2", Name: "In progress"},
+			"o3": {ID: "o3", Name: "Done"},
+		}})
+		addField(Field{ID: "start", Name: "Start date", DataType: FieldTypeDate})
+		addField(Field{ID: "end", Name: "End date", DataType: FieldTypeDate})
+	})
+
+	It("should report nothing when the project matches", func() {
+		Expect(config.ValidateSchema(details)).To(BeEmpty())
+	})
+
+	It("should report missing fields and options", func() {
+		delete(details.FieldsByName, "Start date")
+		status := details.FieldsByName["Status"].(SingleSelectField)
+		delete(status.Options, "o3")
+
+		Expect(config.ValidateSchema(details)).To(ConsistOf(
+			SchemaProblem{Field: "Status", Option: "Done", Problem: "option not found"},
+			SchemaProblem{Field: "Start date", Problem: "field not found"},
+		))
+	})
+
+	It("should report fields with the wrong type", func() {
+		addField(Field{ID: "end", Name: "End date", DataType: FieldTypeText})
+		config.Transitions.ReopenCountField = "Status"
+
+		Expect(config.ValidateSchema(details)).To(ConsistOf(
+			SchemaProblem{Field: "End date", Problem: "expected a date field, found text"},
+			SchemaProblem{Field: "Status", Problem: "expected a number field, found single select"},
+		))
+	})
+
+	It("should report types without an option in the type field", func() {
+		details.TypeMapping = NewTypeMapping()
+		for _, typeName := range details.TypeMapping.TypeNames() {
+			details.TypeMapping.SetTypeID(typeName, typeName+"-id")
+		}
+		addField(SingleSelectField{ID: "type", Name: "Type", Options: map[string]Field{
+			"t1": {ID: "t1", Name: "Bug"},
+			"t2": {ID: "t2", Name: "Feature"},
+		}})
+		config.TypeField = "Type"
+
+		problems := config.ValidateSchema(details)
+		Expect(problems).To(ContainElement(SchemaProblem{Field: "Type", Option: "Chore", Problem: "option not found"}))
+		Expect(problems).NotTo(ContainElement(HaveField("Option", "Bug")))
+	})
+})
@@ -0,0 +1,31 @@
+package lib
+
+import "slices"
+
+type StatusTransition struct {
+	From string
+	To   string
+}
+
+// IsBackward reports whether the transition moves an item to a status that
+// comes earlier on the board. Statuses missing from the configured order are
+// never considered backward moves.
+func (p *ProjectConfig) IsBackward(t StatusTransition) bool {
+	from := slices.Index(p.Statuses, t.From)
+	to := slices.Index(p.Statuses, t.To)
+	return from != -1 && to != -1 && to < from
+}
+
+// IsReopen reports whether the transition is a backward move out of one of
+// the statuses the policy treats as finished.
+func (p *ProjectConfig) IsReopen(t StatusTransition) bool {
+	return p.IsBackward(t) && slices.Contains(p.Transitions.ReopenFrom, t.From)
+}
+
+// FieldsToClear returns the fields that should be emptied for the transition.
+func (p *ProjectConfig) FieldsToClear(t StatusTransition) []string {
+	if !p.IsBackward(t) {
+		return nil
+	}
+	return p.Transitions.ClearFields
+}
@@ -0,0 +1,51 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status transitions", func() {
+	var config ProjectConfig
+
+	BeforeEach(func() {
+		config = DefaultProjectConfig(1)
+		config.Transitions.ReopenCountField = "Reopened"
+	})
+
+	Describe("IsBackward", func() {
+		It("should detect moves to an earlier status", func() {
+			Expect(config.IsBackward(StatusTransition{From: "Done", To: "In progress"})).To(BeTrue())
+			Expect(config.IsBackward(StatusTransition{From: "In progress", To: "Todo"})).To(BeTrue())
+		})
+
+		It("should not flag forward moves or unknown statuses", func() {
+			Expect(config.IsBackward(StatusTransition{From: "Todo", To: "Done"})).To(BeFalse())
+			Expect(config.IsBackward(StatusTransition{From: "Done", To: "Done"})).To(BeFalse())
+			Expect(config.IsBackward(StatusTransition{From: "", To: "Todo"})).To(BeFalse())
+			Expect(config.IsBackward(StatusTransition{From: "Done", To: "Blocked"})).To(BeFalse())
+		})
+	})
+
+	Describe("IsReopen", func() {
+		It("should only count backward moves out of finished statuses", func() {
+			config.Statuses = append(config.Statuses, "Won't do")
+			config.Transitions.ReopenFrom = append(config.Transitions.ReopenFrom, "Won't do")
+
+			Expect(config.IsReopen(StatusTransition{From: "Done", To: "Todo"})).To(BeTrue())
+			Expect(config.IsReopen(StatusTransition{From: "Won't do", To: "Todo"})).To(BeTrue())
+			Expect(config.IsReopen(StatusTransition{From: "Done", To: "Won't do"})).To(BeFalse())
+			Expect(config.IsReopen(StatusTransition{From: "In progress", To: "Todo"})).To(BeFalse())
+		})
+	})
+
+	Describe("FieldsToClear", func() {
+		It("should clear End date but keep Start date by default", func() {
+			Expect(config.FieldsToClear(StatusTransition{From: "Done", To: "In progress"})).To(Equal([]string{"End date"}))
+		})
+
+		It("should clear nothing on forward moves", func() {
+			Expect(config.FieldsToClear(StatusTransition{From: "In progress", To: "Done"})).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,101 @@
+package lib
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	// TypePolicyTitle always sets the type detected from the title.
+	TypePolicyTitle = "title"
+	// TypePolicyHuman sets the type while it is empty or was last set by the
+	// automation, so a type chosen in the UI is kept.
+	TypePolicyHuman = "human"
+	// TypePolicyEmpty only sets the type of issues without one.
+	TypePolicyEmpty = "empty"
+)
+
+func validateTypePolicy(policy string) error {
+	switch policy {
+	case TypePolicyTitle, TypePolicyHuman, TypePolicyEmpty:
+		return nil
+	}
+	return fmt.Errorf("unknown issue type policy %q", policy)
+}
+
+// TypeAssignments remembers the issue type the automation last set on each
+// issue, keyed by issue node ID. It keeps at most limit issues, dropping the
+// oldest first, and asks lookup about issues it does not know, such as the
+// ones typed before a restart.
+type TypeAssignments struct {
+	mu      sync.Mutex
+	byIssue map[string]string
+	order   []string
+	limit   int
+	lookup  TypeLookup
+}
+
+// TypeLookup returns the type the automation last set on the issue, with ok
+// false when the current type was set by someone else or cannot be told.
+type TypeLookup func(issueID string) (typeID string, ok bool)
+
+func NewTypeAssignments(limit int, lookup TypeLookup) *TypeAssignments {
+	return &TypeAssignments{byIssue: make(map[string]string), limit: limit, lookup: lookup}
+}
+
+func (t *TypeAssignments) Record(issueID, typeID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, known := t.byIssue[issueID]; !known {
+		t.order = append(t.order, issueID)
+	}
+	t.byIssue[issueID] = typeID
+	for t.limit > 0 && len(t.order) > t.limit {
+		delete(t.byIssue, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+func (t *TypeAssignments) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.byIssue)
+}
+
+func (t *TypeAssignments) SetByAutomation(issueID, typeID string) bool {
+	t.mu.Lock()
+	setTypeID, ok := t.byIssue[issueID]
+	t.mu.Unlock()
+	if ok {
+		return setTypeID == typeID
+	}
+	if t.lookup == nil {
+		return false
+	}
+
+	setTypeID, ok = t.lookup(issueID)
+	if !ok {
+		return false
+	}
+	t.Record(issueID, setTypeID)
+	return setTypeID == typeID
+}
+
+// MayReplaceType decides whether the automation may change the type of an
+// issue from currentTypeID to newTypeID under policy.
+func (t *TypeAssignments) MayReplaceType(policy, issueID, currentTypeID, newTypeID string) bool {
+	if currentTypeID == newTypeID {
+		return false
+	}
+	if currentTypeID == "" {
+		return true
+	}
+
+	switch policy {
+	case TypePolicyTitle:
+		return true
+	case TypePolicyHuman:
+		return t.SetByAutomation(issueID, currentTypeID)
+	}
+	return false
+}
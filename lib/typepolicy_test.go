@@ -0,0 +1,77 @@
+package lib
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TypeAssignments", func() {
+	var assignments *TypeAssignments
+
+	BeforeEach(func() {
+		assignments = NewTypeAssignments(0, nil)
+	})
+
+	It("should always fill in an empty type and never repeat the current one", func() {
+		for _, policy := range []string{TypePolicyTitle, TypePolicyHuman, TypePolicyEmpty} {
+			Expect(assignments.MayReplaceType(policy, "issue", "", "bug")).To(BeTrue(), "policy %s", policy)
+			Expect(assignments.MayReplaceType(policy, "issue", "bug", "bug")).To(BeFalse(), "policy %s", policy)
+		}
+	})
+
+	It("should let the title win under the title policy", func() {
+		Expect(assignments.MayReplaceType(TypePolicyTitle, "issue", "feature", "bug")).To(BeTrue())
+	})
+
+	It("should keep existing types under the empty policy", func() {
+		assignments.Record("issue", "feature")
+		Expect(assignments.MayReplaceType(TypePolicyEmpty, "issue", "feature", "bug")).To(BeFalse())
+	})
+
+	Context("under the human policy", func() {
+		It("should replace a type the automation set", func() {
+			assignments.Record("issue", "feature")
+			Expect(assignments.MayReplaceType(TypePolicyHuman, "issue", "feature", "bug")).To(BeTrue())
+		})
+
+		It("should keep a type chosen by a person", func() {
+			Expect(assignments.MayReplaceType(TypePolicyHuman, "issue", "feature", "bug")).To(BeFalse())
+
+			assignments.Record("issue", "spike")
+			Expect(assignments.MayReplaceType(TypePolicyHuman, "issue", "feature", "bug")).To(BeFalse())
+		})
+	})
+
+	It("should ask the lookup about issues it has not seen", func() {
+		lookups := 0
+		assignments = NewTypeAssignments(0, func(issueID string) (string, bool) {
+			lookups++
+			return "feature", issueID == "typed-by-automation"
+		})
+
+		Expect(assignments.MayReplaceType(TypePolicyHuman, "typed-by-automation", "feature", "bug")).To(BeTrue())
+		Expect(assignments.MayReplaceType(TypePolicyHuman, "typed-by-person", "feature", "bug")).To(BeFalse())
+		Expect(assignments.MayReplaceType(TypePolicyHuman, "typed-by-automation", "feature", "bug")).To(BeTrue())
+		Expect(lookups).To(Equal(2), "answers from the automation are remembered")
+	})
+
+	It("should forget the oldest issues past its limit", func() {
+		assignments = NewTypeAssignments(2, nil)
+		assignments.Record("first", "feature")
+		assignments.Record("second", "feature")
+		assignments.Record("first", "bug")
+		assignments.Record("third", "feature")
+
+		Expect(assignments.Len()).To(Equal(2))
+		Expect(assignments.SetByAutomation("first", "bug")).To(BeFalse())
+		Expect(assignments.SetByAutomation("third", "feature")).To(BeTrue())
+	})
+
+	It("should treat clearing the type like any other change", func() {
+		Expect(assignments.MayReplaceType(TypePolicyHuman, "issue", "bug", "")).To(BeFalse())
+		assignments.Record("issue", "bug")
+		Expect(assignments.MayReplaceType(TypePolicyHuman, "issue", "bug", "")).To(BeTrue())
+		Expect(assignments.MayReplaceType(TypePolicyEmpty, "issue", "bug", "")).To(BeFalse())
+		Expect(assignments.MayReplaceType(TypePolicyHuman, "issue", "", "")).To(BeFalse())
+	})
+})
@@ -1,45 +1,169 @@
 package lib
 
-import "strings"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
 
 type TypeMapping struct {
 	PrefixToType map[string]string
 	TypeToID     map[string]string
+	// RepositoryPrefixToType holds the prefixes a repository adds to or
+	// overrides in PrefixToType, keyed by the repository full name.
+	RepositoryPrefixToType map[string]map[string]string
+	// Matchers are tried in order until one finds a tag that resolves to a
+	// type.
+	Matchers []TitleMatcher
+}
+
+func DefaultTypePrefixes() TypePrefixes {
+	return TypePrefixes{
+		"Feature":   {"feat"},
+		"Bug":       {"bug"},
+		"Docs":      {"docs"},
+		"Blog":      {"blog"},
+		"Interrupt": {"interrupt"},
+		"Spike":     {"spike"},
+		"Chore":     {"chore"},
+	}
 }
 
 func NewTypeMapping() *TypeMapping {
-	return &TypeMapping{
-		PrefixToType: map[string]string{
-			"feat":      "Feature",
-			"bug":       "Bug",
-			"docs":      "Docs",
-			"blog":      "Blog",
-			"interrupt": "Interrupt",
-			"spike":     "Spike",
-			"chore":     "Chore",
-		},
-		TypeToID: make(map[string]string),
+	tm, _ := NewTypeMappingFromConfig(IssueTypesConfig{Prefixes: DefaultTypePrefixes()})
+	return tm
+}
+
+func NewTypeMappingFromConfig(config IssueTypesConfig) (*TypeMapping, error) {
+	prefixToType, err := prefixesToTypes(config.Prefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	tm := &TypeMapping{
+		PrefixToType:           prefixToType,
+		TypeToID:               make(map[string]string),
+		RepositoryPrefixToType: make(map[string]map[string]string),
 	}
+	for repository, prefixes := range config.Repositories {
+		repositoryPrefixToType, err := prefixesToTypes(prefixes)
+		if err != nil {
+			return nil, fmt.Errorf("repository %s: %w", repository, err)
+		}
+		tm.RepositoryPrefixToType[repository] = repositoryPrefixToType
+	}
+	matcherConfigs := config.Matchers
+	if len(matcherConfigs) == 0 {
+		matcherConfigs = DefaultMatchers()
+	}
+	for _, matcherConfig := range matcherConfigs {
+		matcher, err := NewTitleMatcher(matcherConfig)
+		if err != nil {
+			return nil, err
+		}
+		tm.Matchers = append(tm.Matchers, matcher)
+	}
+	return tm, nil
 }
 
-func (tm *TypeMapping) GetTypeFromTitle(title string) (string, bool) {
-	colonIndex := strings.Index(title, ":")
-	if colonIndex == -1 {
-		return "", false
+func prefixesToTypes(prefixes TypePrefixes) (map[string]string, error) {
+	prefixToType := make(map[string]string)
+	for typeName, aliases := range prefixes {
+		for _, prefix := range aliases {
+			prefix = strings.ToLower(strings.TrimSpace(prefix))
+			if existing, ok := prefixToType[prefix]; ok && existing != typeName {
+				return nil, fmt.Errorf("prefix %q maps to both %s and %s", prefix, existing, typeName)
+			}
+			prefixToType[prefix] = typeName
+		}
 	}
+	return prefixToType, nil
+}
 
-	prefix := strings.TrimSpace(title[:colonIndex])
+// ForRepository returns the mapping to use for titles in repository, with its
+// own prefixes layered over the organization ones.
+func (tm *TypeMapping) ForRepository(repository string) *TypeMapping {
+	overrides, ok := tm.RepositoryPrefixToType[repository]
+	if !ok {
+		return tm
+	}
 
-	if parenIndex := strings.Index(prefix, "("); parenIndex != -1 {
-		prefix = strings.TrimSpace(prefix[:parenIndex])
+	prefixToType := maps.Clone(tm.PrefixToType)
+	maps.Copy(prefixToType, overrides)
+	return &TypeMapping{
+		PrefixToType: prefixToType,
+		TypeToID:     tm.TypeToID,
+		Matchers:     tm.Matchers,
 	}
+}
 
-	prefix = strings.ToLower(prefix)
+// TypeNames returns every type name a prefix maps to, sorted.
+func (tm *TypeMapping) TypeNames() []string {
+	names := make(map[string]bool)
+	for _, typeName := range tm.PrefixToType {
+		names[typeName] = true
+	}
+	for _, prefixToType := range tm.RepositoryPrefixToType {
+		for _, typeName := range prefixToType {
+			names[typeName] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(names))
+}
 
-	if typeName, exists := tm.PrefixToType[prefix]; exists {
-		return typeName, true
+// UnknownTypes returns the type names prefixes map to that are not issue
+// types of the organization.
+func (tm *TypeMapping) UnknownTypes() []string {
+	var unknown []string
+	for _, typeName := range tm.TypeNames() {
+		if _, ok := tm.TypeToID[typeName]; !ok {
+			unknown = append(unknown, typeName)
+		}
 	}
+	return unknown
+}
+
+func (tm *TypeMapping) GetTypeFromTitle(title string) (string, bool) {
+	match, found := tm.Match(title)
+	return match.Type, found
+}
 
+// Match runs the matchers in order and returns the first match whose tag is a
+// known prefix or type name.
+func (tm *TypeMapping) Match(title string) (TitleMatch, bool) {
+	for _, matcher := range tm.Matchers {
+		match, found := matcher.Match(title)
+		if !found {
+			continue
+		}
+		if match.Type == "" {
+			match.Type, found = tm.resolveTag(match.Tag)
+		}
+		if found {
+			return match, true
+		}
+	}
+	return TitleMatch{}, false
+}
+
+// resolveTag looks tag up as a prefix first and then as a type name, so
+// "[Bug]" and "[bug]" both resolve.
+func (tm *TypeMapping) resolveTag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if typeName, exists := tm.PrefixToType[tag]; exists {
+		return typeName, true
+	}
+	for _, typeName := range tm.PrefixToType {
+		if strings.ToLower(typeName) == tag {
+			return typeName, true
+		}
+	}
+	for typeName := range tm.TypeToID {
+		if strings.ToLower(typeName) == tag {
+			return typeName, true
+		}
+	}
 	return "", false
 }
 
@@ -218,4 +218,91 @@ var _ = Describe("TypeMapping", func() {
 			Expect(typeMapping.TypeToID).To(BeEmpty())
 		})
 	})
+
+	Describe("NewTypeMappingFromConfig", func() {
+		var config IssueTypesConfig
+
+		BeforeEach(func() {
+			config = IssueTypesConfig{
+				Prefixes: TypePrefixes{
+					"Bug":     {"bug", "fix"},
+					"Feature": {"feat", "perf", "refactor"},
+				},
+				Repositories: map[string]TypePrefixes{
+					"syntasso/kratix": {
+						"Epic":  {"epic"},
+						"Chore": {"refactor"},
+					},
+				},
+			}
+		})
+
+		It("should map every alias to its type", func() {
+			typeMapping, err := NewTypeMappingFromConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+
+			for title, expectedType := range map[string]string{
+				"fix: crash on start":    "Bug",
+				"perf(api): faster list": "Feature",
+				"Refactor: tidy up":      "Feature",
+			} {
+				typeName, found := typeMapping.GetTypeFromTitle(title)
+				Expect(found).To(BeTrue(), "Expected to find type for title: %s", title)
+				Expect(typeName).To(Equal(expectedType))
+			}
+
+			_, found := typeMapping.GetTypeFromTitle("epic: big thing")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should layer repository prefixes over the organization ones", func() {
+			typeMapping, err := NewTypeMappingFromConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+			repoMapping := typeMapping.ForRepository("syntasso/kratix")
+
+			typeName, found := repoMapping.GetTypeFromTitle("epic: big thing")
+			Expect(found).To(BeTrue())
+			Expect(typeName).To(Equal("Epic"))
+
+			typeName, _ = repoMapping.GetTypeFromTitle("refactor: tidy up")
+			Expect(typeName).To(Equal("Chore"))
+
+			typeName, _ = repoMapping.GetTypeFromTitle("fix: crash")
+			Expect(typeName).To(Equal("Bug"))
+
+			Expect(typeMapping.ForRepository("syntasso/other")).To(BeIdenticalTo(typeMapping))
+		})
+
+		It("should share type IDs with repository mappings", func() {
+			typeMapping, err := NewTypeMappingFromConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+			typeMapping.SetTypeID("Epic", "epic-id")
+
+			id, found := typeMapping.ForRepository("syntasso/kratix").GetTypeID("Epic")
+			Expect(found).To(BeTrue())
+			Expect(id).To(Equal("epic-id"))
+		})
+
+		It("should reject a prefix mapped to two types", func() {
+			config.Prefixes["Chore"] = []string{"FIX"}
+			_, err := NewTypeMappingFromConfig(config)
+			Expect(err).To(MatchError(ContainSubstring(`prefix "fix" maps to both`)))
+		})
+
+		It("should report targets that are not organization issue types", func() {
+			typeMapping, err := NewTypeMappingFromConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+			typeMapping.SetTypeID("Bug", "bug-id")
+			typeMapping.SetTypeID("Feature", "feature-id")
+
+			Expect(typeMapping.UnknownTypes()).To(Equal([]string{"Chore", "Epic"}))
+		})
+
+		It("should list every type name prefixes map to", func() {
+			typeMapping, err := NewTypeMappingFromConfig(config)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(typeMapping.TypeNames()).To(Equal([]string{"Bug", "Chore", "Epic", "Feature"}))
+		})
+	})
 })
@@ -6,12 +6,13 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/kirederik/ghproject/lib"
-	"github.com/shurcooL/githubv4"
 )
 
 type ProjectInfo struct {
@@ -26,6 +27,22 @@ type Node struct {
 
 type GithubEntity struct {
 	Name string `json:"login"`
+	Type string `json:"type"`
+}
+
+type Label struct {
+	Name string `json:"name"`
+}
+
+type IssueType struct {
+	Name string `json:"name"`
+}
+
+type ProjectV2 struct {
+	ID     int64  `json:"id"`
+	NodeID string `json:"node_id"`
+	Number int64  `json:"number"`
+	Title  string `json:"title"`
 }
 
 type ProjectV2Item struct {
@@ -36,6 +53,8 @@ type ProjectV2Item struct {
 	CreatedAt     string       `json:"created_at"`
 	UpdatedAt     string       `json:"updated_at"`
 	ArchivedAt    string       `json:"archived_at"`
+	ContentNodeID string       `json:"content_node_id"`
+	ContentType   string       `json:"content_type"`
 }
 
 type PullRequest struct {
@@ -43,7 +62,18 @@ type PullRequest struct {
 	NodeID string       `json:"node_id"`
 	Number int64        `json:"number"`
 	State  string       `json:"state"`
+	Title  string       `json:"title"`
+	Body   string       `json:"body"`
+	Draft  bool         `json:"draft"`
+	Merged bool         `json:"merged"`
 	User   GithubEntity `json:"user"`
+	Head   GitRef       `json:"head"`
+	Labels []Label      `json:"labels"`
+}
+
+type GitRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
 }
 
 type Issue struct {
@@ -51,7 +81,13 @@ type Issue struct {
 	NodeID string `json:"node_id"`
 	Number int64  `json:"number"`
 	State  string `json:"state"`
-	Title  string `json:"title"`
+	// StateReason is "completed", "not_planned" or "reopened".
+	StateReason string       `json:"state_reason"`
+	Title       string       `json:"title"`
+	Body        string       `json:"body"`
+	User        GithubEntity `json:"user"`
+	Labels      []Label      `json:"labels"`
+	Type        *IssueType   `json:"type"`
 }
 
 type Repository struct {
@@ -69,6 +105,7 @@ type GithubIncomingEvent struct {
 }
 type EventPayload struct {
 	Action        string         `json:"action"`
+	ProjectV2     *ProjectV2     `json:"projects_v2,omitempty"`
 	ProjectV2Item *ProjectV2Item `json:"projects_v2_item,omitempty"`
 	Changes       Changeset      `json:"changes"`
 	Organization  GithubEntity   `json:"organization"`
@@ -76,17 +113,30 @@ type EventPayload struct {
 	PullRequest   *PullRequest   `json:"pull_request,omitempty"`
 	Issue         *Issue         `json:"issue,omitempty"`
 	Repository    *Repository    `json:"repository,omitempty"`
+	// Ref and RefType are set on create and delete events.
+	Ref     string `json:"ref"`
+	RefType string `json:"ref_type"`
 }
 
+// maxTypeAssignments bounds how many issues the type policy remembers; older
+// ones are looked up on the issue timeline again.
+const maxTypeAssignments = 10000
+
 const (
-	ReorderAction       = "reorder"
+	ReorderAction       = "reordered"
 	EditedAction        = "edited"
 	ReorderChangesetKey = "previous_projects_v2_item_node_id"
 )
 
 var (
-	projectDetails *lib.ProjectDetails
-	ghClient       *lib.GithubClient
+	config          *lib.Config
+	projects        []*project
+	ghClient        *lib.GithubClient
+	typeAssignments = lib.NewTypeAssignments(maxTypeAssignments, typeSetByAutomation)
+	classifier      *lib.Classifier
+	loopGuard       *lib.LoopGuard
+	// branchConvention finds the issue a new branch is named after.
+	branchConvention *lib.BranchConvention
 )
 
 func IncomingRequestHandler(w http.ResponseWriter, r *http.Request) {
@@ -108,89 +158,486 @@ func IncomingRequestHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Println("Request body: ", string(body[:100]))
 	fmt.Println("Event action: ", event.Action)
 
+	if config.LoopPrevention.IsAutomation(event.Sender.Name) {
+		fmt.Printf("Ignoring event sent by the automation itself (%s)\n", event.Sender.Name)
+		w.Write([]byte("OK"))
+		return
+	}
+
+	if event.ProjectV2 != nil {
+		handleProjectV2(event)
+	}
 	if event.ProjectV2Item != nil {
 		handleProjectV2Item(event)
 	}
 	if event.PullRequest != nil {
 		handlePullRequest(event)
 	}
+	eventName := r.Header.Get("X-GitHub-Event")
 	if event.Issue != nil {
-		handleIssue(event)
+		handleIssue(event, eventName)
+	}
+	if eventName == "create" && event.RefType == "branch" {
+		handleBranchCreated(event)
 	}
 
 	w.Write([]byte("OK"))
 }
 
-func handleIssue(event EventPayload) {
+// handleIssue handles issues events and the other events that carry an
+// issue, such as issue_comment, which share some action names. eventName is
+// the X-GitHub-Event header.
+func handleIssue(event EventPayload, eventName string) {
 	fmt.Printf("Issue event: %s, issue %s#%d\n", event.Action, event.Repository.FullName, event.Issue.Number)
-	projectID := projectDetails.ID
+	content := itemContent{
+		Repository: event.Repository.FullName,
+		Number:     int(event.Issue.Number),
+		NodeID:     event.Issue.NodeID,
+		Title:      event.Issue.Title,
+		Body:       event.Issue.Body,
+		Labels:     labelNames(event.Issue.Labels),
+	}
 	if slices.Contains([]string{"edited", "reopened", "opened", "created"}, event.Action) {
-		fmt.Printf("Adding issue %s#%d to project\n", event.Repository.FullName, event.Issue.Number)
-		itemID, err := ghClient.AddNodeToProject(projectID, event.Issue.NodeID)
-		if err != nil {
-			log.Printf("Failed to add issue to project: %v", err)
-			return
+		subject := issueSubject(event)
+		for _, p := range projects {
+			if !p.accepts(subject) {
+				continue
+			}
+			fmt.Printf("Adding issue %s#%d to project %d\n", event.Repository.FullName, event.Issue.Number, p.config.Number)
+			itemID, err := p.addItem(event.Issue.NodeID)
+			if err != nil {
+				log.Printf("Failed to add issue to project: %v", err)
+				continue
+			}
+			fmt.Printf("Added issue to project as item: %s\n", itemID)
+			applyTitleFields(p, itemID, content)
+		}
+		labelBreakingChange(content)
+
+		assignTypeToIssue(event.Repository.FullName, event.Issue.Title, previousTitle(event.Changes), event.Issue.NodeID)
+		if event.Action == "opened" {
+			classifyIssue(content, event.Issue.Type != nil)
 		}
-		fmt.Printf("Added issue to project as item: %s\n", itemID)
+	}
 
-		assignTypeToIssue(event.Issue.Title, event.Issue.NodeID)
+	// Comments are also created, edited and deleted, so only issues events
+	// may change the issue's items.
+	if eventName != "issues" {
+		return
+	}
+	switch event.Action {
+	case "closed":
+		closeIssue(event)
+	case "reopened":
+		moveIssue(event, func(statuses lib.IssueStatuses) string { return statuses.Reopened })
+	case "transferred":
+		// The issue lives on in the new repository and is added from there.
+		removeIssueItems(event, ghClient.ArchiveProjectItem)
+	case "deleted":
+		removeIssueItems(event, ghClient.DeleteProjectItem)
 	}
 }
 
-func assignTypeToIssue(title, issueNodeID string) {
+// assignTypeToIssue sets the type detected from the title. When the title was
+// edited, previousTitle is the old one: if its prefix was removed the type it
+// selected is cleared, and the type it selected counts as set from the title
+// rather than by a person.
+func assignTypeToIssue(repository, title, previousTitle, issueNodeID string) {
 	fmt.Printf("Attempting to assign type to issue with title: %q\n", title)
 
-	typeName, found := projectDetails.TypeMapping.GetTypeFromTitle(title)
-	if !found {
+	typeMapping := currentTypeMapping().ForRepository(repository)
+	previousTypeID := ""
+	if previousTitle != "" {
+		if previousTypeName, found := typeMapping.GetTypeFromTitle(previousTitle); found {
+			previousTypeID, _ = typeMapping.GetTypeID(previousTypeName)
+		}
+	}
+
+	typeName, found := typeMapping.GetTypeFromTitle(title)
+	if !found && previousTypeID == "" {
 		fmt.Printf("No matching type found for title: %s\n", title)
 		return
 	}
 
-	fmt.Printf("Detected type: %s\n", typeName)
+	issueTypeID := ""
+	if found {
+		fmt.Printf("Detected type: %s\n", typeName)
 
-	issueTypeID, exists := projectDetails.TypeMapping.GetTypeID(typeName)
-	if !exists {
-		fmt.Printf("Type '%s' not found in organization issue types\n", typeName)
+		var exists bool
+		issueTypeID, exists = typeMapping.GetTypeID(typeName)
+		if !exists {
+			fmt.Printf("Type '%s' not found in organization issue types\n", typeName)
+			return
+		}
+	} else {
+		fmt.Printf("Title prefix removed from %q\n", previousTitle)
+	}
+
+	currentTypeID, currentTypeName, err := ghClient.FetchIssueType(issueNodeID)
+	if err != nil {
+		log.Printf("Failed to fetch current issue type: %v", err)
+		return
+	}
+	if previousTypeID != "" && currentTypeID == previousTypeID {
+		typeAssignments.Record(issueNodeID, currentTypeID)
+	}
+	if !typeAssignments.MayReplaceType(config.IssueTypes.Policy, issueNodeID, currentTypeID, issueTypeID) {
+		fmt.Printf("Keeping current type '%s' (policy %q)\n", currentTypeName, config.IssueTypes.Policy)
 		return
 	}
 
 	// Update the issue with the detected type
-	err := ghClient.UpdateIssueType(issueNodeID, issueTypeID)
+	err = ghClient.UpdateIssueType(issueNodeID, issueTypeID)
 	if err != nil {
 		log.Printf("Failed to update issue type: %v", err)
 		return
 	}
+	typeAssignments.Record(issueNodeID, issueTypeID)
 
+	if issueTypeID == "" {
+		fmt.Printf("Cleared type '%s' from issue\n", currentTypeName)
+		return
+	}
 	fmt.Printf("Successfully assigned type '%s' to issue\n", typeName)
 }
 
+// typeSetByAutomation reads the issue timeline to tell whether the automation
+// set the current type, for issues typed before the last restart.
+func typeSetByAutomation(issueID string) (string, bool) {
+	typeID, login, err := ghClient.FetchLastTypeChange(issueID)
+	if err != nil {
+		log.Printf("Failed to fetch the issue type history: %v", err)
+		return "", false
+	}
+	return typeID, login != "" && config.LoopPrevention.IsAutomation(login)
+}
+
+// classifyIssue suggests a type for an untyped issue whose title has none.
+// Confident suggestions are applied; the rest are posted as a comment when
+// configured. typed reports whether the event already carried a type.
+func classifyIssue(content itemContent, typed bool) {
+	if classifier == nil || typed {
+		return
+	}
+	if _, found := content.typeMapping().Match(content.Title); found {
+		return
+	}
+	// The type may have been set since the event was sent.
+	currentTypeID, _, err := ghClient.FetchIssueType(content.NodeID)
+	if err != nil {
+		log.Printf("Failed to fetch current issue type: %v", err)
+		return
+	}
+	if currentTypeID != "" {
+		return
+	}
+
+	typeName, confidence := classifier.Classify(content.Title + "\n" + content.Body)
+	if typeName == "" {
+		fmt.Println("Classifier has no suggestion")
+		return
+	}
+	fmt.Printf("Classifier suggests type '%s' with confidence %.2f\n", typeName, confidence)
+
+	if confidence < config.Classifier.Threshold {
+		if !config.Classifier.Suggest {
+			return
+		}
+		comment := fmt.Sprintf("This issue looks like a **%s** (%.0f%% confident). If that is right, please set its type or add a prefix to the title.", typeName, confidence*100)
+		if err := ghClient.AddComment(content.NodeID, comment); err != nil {
+			log.Printf("Failed to comment type suggestion: %v", err)
+		}
+		return
+	}
+
+	issueTypeID, exists := content.typeMapping().GetTypeID(typeName)
+	if !exists {
+		fmt.Printf("Type '%s' not found in organization issue types\n", typeName)
+		return
+	}
+	if err := ghClient.UpdateIssueType(content.NodeID, issueTypeID); err != nil {
+		log.Printf("Failed to update issue type: %v", err)
+		return
+	}
+	typeAssignments.Record(content.NodeID, issueTypeID)
+	fmt.Printf("Assigned classified type '%s' to issue\n", typeName)
+}
+
+// previousTitle returns the title before an edited event changed it.
+func previousTitle(changes Changeset) string {
+	from, _ := changes["title"]["from"].(string)
+	return from
+}
+
 func handlePullRequest(event EventPayload) {
 	fmt.Printf("Pull request event: %s, PR %s#%d\n", event.Action, event.Repository.FullName, event.PullRequest.Number)
-	projectID := projectDetails.ID
-	if event.Action == "opened" {
-		if event.PullRequest.User.Name == "" {
-			log.Printf("PR %s#%d has no author login in payload, skipping assignee update", event.Repository.FullName, event.PullRequest.Number)
+	content := itemContent{
+		Repository: event.Repository.FullName,
+		Number:     int(event.PullRequest.Number),
+		NodeID:     event.PullRequest.NodeID,
+		Title:      event.PullRequest.Title,
+		Body:       event.PullRequest.Body,
+		Labels:     labelNames(event.PullRequest.Labels),
+	}
+	subject := pullRequestSubject(event)
+	if config.TitleLint.Mode != "" && slices.Contains([]string{"opened", "edited", "synchronize", "reopened"}, event.Action) {
+		lintPullRequestTitle(event)
+	}
+
+	switch event.Action {
+	case "opened":
+		assignPullRequest(event)
+
+		for _, p := range projects {
+			if !acceptsPullRequest(p, event, subject) {
+				continue
+			}
+			fmt.Printf("Adding PR %s#%d to project %d\n", event.Repository.FullName, event.PullRequest.Number, p.config.Number)
+			itemID, err := p.addItem(event.PullRequest.NodeID)
+			if err != nil {
+				log.Printf("Failed to add PR to project: %v", err)
+				continue
+			}
+			fmt.Printf("Added PR to project as item: %s\n", itemID)
+			applyTitleFields(p, itemID, content)
+		}
+		labelBreakingChange(content)
+
+		if isBotPullRequest(event) && config.Bots.Mode == lib.BotRoute && config.Bots.Status != "" {
+			movePullRequest(event, func(lib.PullRequestStatuses) string { return config.Bots.Status })
+		} else if event.PullRequest.Draft {
+			movePullRequest(event, func(statuses lib.PullRequestStatuses) string { return statuses.Draft })
 		} else {
-			err := ghClient.AssignPullRequestToUser(event.PullRequest.NodeID, event.PullRequest.User.Name)
+			movePullRequest(event, func(statuses lib.PullRequestStatuses) string { return statuses.InReview })
+		}
+		startIssues(event.Repository.FullName, lib.ReferencedIssueNumbers(event.PullRequest.Title+"\n"+event.PullRequest.Body))
+	case EditedAction:
+		if previousTitle(event.Changes) == "" {
+			break
+		}
+		for _, p := range projects {
+			if !acceptsPullRequest(p, event, subject) {
+				continue
+			}
+			// Adding an item that is already on the board returns its ID.
+			itemID, err := p.addItem(event.PullRequest.NodeID)
 			if err != nil {
-				log.Printf("Failed to assign PR %s#%d to %s: %v", event.Repository.FullName, event.PullRequest.Number, event.PullRequest.User.Name, err)
-			} else {
-				fmt.Printf("Assigned PR %s#%d to %s\n", event.Repository.FullName, event.PullRequest.Number, event.PullRequest.User.Name)
+				log.Printf("Failed to find PR in project: %v", err)
+				continue
 			}
+			applyTitleFields(p, itemID, content)
 		}
+		labelBreakingChange(content)
+	case "ready_for_review":
+		movePullRequest(event, func(statuses lib.PullRequestStatuses) string { return statuses.InReview })
+	case "review_requested":
+		if !event.PullRequest.Draft {
+			movePullRequest(event, func(statuses lib.PullRequestStatuses) string { return statuses.InReview })
+		}
+	case "converted_to_draft":
+		movePullRequest(event, func(statuses lib.PullRequestStatuses) string { return statuses.Draft })
+	case "closed":
+		if event.PullRequest.Merged {
+			// GitHub only closes linked issues itself for merges into the
+			// default branch.
+			movePullRequest(event, func(statuses lib.PullRequestStatuses) string { return statuses.Merged })
+		} else {
+			revertClosedPullRequest(event)
+		}
+	}
+}
 
-		fmt.Printf("Adding PR %s#%d to project\n", event.Repository.FullName, event.PullRequest.Number)
-		itemID, err := ghClient.AddNodeToProject(projectID, event.PullRequest.NodeID)
-		if err != nil {
-			log.Printf("Failed to add PR to project: %v", err)
+// assignPullRequest assigns the author, or the on-duty owner for bot pull
+// requests as bots cannot be assigned.
+func assignPullRequest(event EventPayload) {
+	assignee := event.PullRequest.User.Name
+	if isBotPullRequest(event) {
+		assignee = config.Bots.Assignee
+		if assignee == "" {
+			fmt.Printf("PR %s#%d was opened by %s, skipping assignee update\n", event.Repository.FullName, event.PullRequest.Number, event.PullRequest.User.Name)
 			return
 		}
-		fmt.Printf("Added PR to project as item: %s\n", itemID)
+	}
+	if assignee == "" {
+		log.Printf("PR %s#%d has no author login in payload, skipping assignee update", event.Repository.FullName, event.PullRequest.Number)
+		return
+	}
+
+	err := ghClient.AssignPullRequestToUser(event.PullRequest.NodeID, assignee)
+	if err != nil {
+		log.Printf("Failed to assign PR %s#%d to %s: %v", event.Repository.FullName, event.PullRequest.Number, assignee, err)
+	} else {
+		fmt.Printf("Assigned PR %s#%d to %s\n", event.Repository.FullName, event.PullRequest.Number, assignee)
+	}
+}
+
+// closingIssues returns the issues the pull request closes, which move
+// through the board together with it.
+func closingIssues(event EventPayload) []lib.ClosingIssue {
+	issues, err := ghClient.FetchClosingIssues(event.PullRequest.NodeID)
+	if err != nil {
+		log.Printf("Failed to fetch issues closed by PR %s#%d: %v", event.Repository.FullName, event.PullRequest.Number, err)
+	}
+	fmt.Printf("PR %s#%d has %d linked issues\n", event.Repository.FullName, event.PullRequest.Number, len(issues))
+	return issues
+}
+
+// pullRequestContentIDs returns the pull request and the linked issues that
+// the project takes. Each linked issue is checked against the filter on its
+// own, as it may match where the pull request does not.
+func pullRequestContentIDs(p *project, event EventPayload, subject lib.FilterSubject, issues []lib.ClosingIssue) []string {
+	var contentIDs []string
+	if acceptsPullRequest(p, event, subject) {
+		contentIDs = append(contentIDs, event.PullRequest.NodeID)
+	}
+	for _, issue := range issues {
+		if p.accepts(closingIssueSubject(issue)) {
+			contentIDs = append(contentIDs, issue.ID)
+		}
+	}
+	return contentIDs
+}
+
+// movePullRequest moves the pull request and its linked issues to the status
+// pick selects in each project.
+func movePullRequest(event EventPayload, pick func(lib.PullRequestStatuses) string) {
+	issues := closingIssues(event)
+	subject := pullRequestSubject(event)
+	for _, p := range projects {
+		status := pick(p.config.PullRequestStatuses)
+		if status == "" {
+			continue
+		}
+		for _, contentID := range pullRequestContentIDs(p, event, subject, issues) {
+			moveContentToStatus(p, contentID, status, false)
+		}
+	}
+}
+
+// revertClosedPullRequest moves the items of a pull request closed without
+// merging back to where they were before the pull request moved them.
+func revertClosedPullRequest(event EventPayload) {
+	issues := closingIssues(event)
+	subject := pullRequestSubject(event)
+	for _, p := range projects {
+		for _, contentID := range pullRequestContentIDs(p, event, subject, issues) {
+			itemID, err := p.findItem(contentID)
+			if err != nil {
+				log.Printf("Failed to find %s in project %d: %v", contentID, p.config.Number, err)
+				continue
+			}
+			if itemID == "" {
+				continue
+			}
+			// Items a person moved, or that the automation never touched,
+			// stay where they are.
+			status, moved := p.takePreviousStatus(itemID)
+			if !moved {
+				continue
+			}
+			if status == "" {
+				status = p.config.PullRequestStatuses.Closed
+			}
+			if status == "" {
+				continue
+			}
+			if err := moveItemToStatus(p, itemID, status, false); err != nil {
+				log.Printf("Failed to move %s to %q: %v", contentID, status, err)
+			}
+		}
 	}
 }
 
+// lintPullRequestTitle reports on the head commit whether the title selects a
+// type, so the merge queue can require it.
+func lintPullRequestTitle(event EventPayload) {
+	owner, repository, ok := strings.Cut(event.Repository.FullName, "/")
+	if !ok {
+		log.Printf("Unexpected repository name %q", event.Repository.FullName)
+		return
+	}
+	result := currentTypeMapping().ForRepository(event.Repository.FullName).LintTitle(event.PullRequest.Title)
+
+	var err error
+	switch config.TitleLint.Mode {
+	case lib.TitleLintCheck:
+		run := lib.CheckRun{
+			Name:       config.TitleLint.Name,
+			HeadSHA:    event.PullRequest.Head.SHA,
+			Status:     "completed",
+			Conclusion: "success",
+			Output: lib.CheckRunOutput{
+				Title:   "Title selects a type",
+				Summary: result.Summary,
+			},
+		}
+		if !result.Valid {
+			run.Conclusion = "failure"
+			run.Output.Title = "Title does not select a type"
+		}
+		err = ghClient.CreateCheckRun(owner, repository, run)
+	case lib.TitleLintStatus:
+		status := lib.CommitStatus{
+			State:       "success",
+			Context:     config.TitleLint.Name,
+			Description: fmt.Sprintf("Type: %s", result.Type),
+		}
+		if !result.Valid {
+			status.State = "failure"
+			status.Description = fmt.Sprintf("%s; expected %s; prefixes: %s", result.Rule, strings.ReplaceAll(result.Format, "`", ""), strings.Join(result.Prefixes, ", "))
+			// Commit status descriptions are limited to 140 characters.
+			if len(status.Description) > 140 {
+				status.Description = status.Description[:137] + "..."
+			}
+		}
+		err = ghClient.CreateCommitStatus(owner, repository, event.PullRequest.Head.SHA, status)
+	}
+	if err != nil {
+		log.Printf("Failed to report title lint for PR %s#%d: %v", event.Repository.FullName, event.PullRequest.Number, err)
+		return
+	}
+	fmt.Printf("Reported title lint for PR %s#%d: valid=%t\n", event.Repository.FullName, event.PullRequest.Number, result.Valid)
+}
+
 func handleProjectV2Item(event EventPayload) {
+	p := projectByNodeID(event.ProjectV2Item.ProjectNodeID)
+	if p == nil {
+		fmt.Printf("Ignoring item from unknown project %s\n", event.ProjectV2Item.ProjectNodeID)
+		return
+	}
+
+	details := p.snapshot()
+
 	switch event.Action {
+	case "created", "converted":
+		handleItemAdded(p, event)
+	case "deleted":
+		p.members.Remove(event.ProjectV2Item.NodeID)
+		p.archive.Forget(event.ProjectV2Item.NodeID)
+		p.takePreviousStatus(event.ProjectV2Item.NodeID)
+	case "archived":
+		fmt.Printf("Item %s archived at %s\n", event.ProjectV2Item.NodeID, event.ProjectV2Item.ArchivedAt)
+		p.archive.Archive(event.ProjectV2Item.NodeID)
+		p.members.Remove(event.ProjectV2Item.NodeID)
+		p.takePreviousStatus(event.ProjectV2Item.NodeID)
+	case "restored":
+		p.archive.Restore(event.ProjectV2Item.NodeID)
+		if event.ProjectV2Item.ContentNodeID != "" {
+			p.members.Add(event.ProjectV2Item.ContentNodeID, event.ProjectV2Item.NodeID)
+		}
+	case ReorderAction:
+		if !p.config.Ranking.Enabled() {
+			break
+		}
+		previous := event.Changes[ReorderChangesetKey]
+		item, err := ghClient.FetchProjectItem(event.ProjectV2Item.NodeID)
+		if err != nil {
+			log.Printf("Failed to fetch reordered item %s: %v", event.ProjectV2Item.NodeID, err)
+			break
+		}
+		column := item.Values[p.config.StatusField]
+		fmt.Printf("Item %s moved after %v, reranking %q in project %d\n", event.ProjectV2Item.NodeID, previous["to"], column, p.config.Number)
+		p.scheduleRerank(column)
 	case EditedAction:
 		fmt.Println("Project item edited")
 		fieldChanged, ok := event.Changes["field_value"]
@@ -203,52 +650,44 @@ func handleProjectV2Item(event EventPayload) {
 		fieldNodeID := fieldChanged["field_node_id"].(string)
 		fieldType := fieldChanged["field_type"]
 
+		now := time.Now()
+		if loopGuard.IsEcho(event.ProjectV2Item.NodeID, fieldNodeID, now) {
+			fmt.Printf("Ignoring our own write to field %s of item %s\n", fieldNodeID, event.ProjectV2Item.NodeID)
+			break
+		}
+		if depth := loopGuard.ChainDepth(event.ProjectV2Item.NodeID, now); depth > config.LoopPrevention.MaxChainDepth {
+			log.Printf("Ignoring edit of item %s: the automation reacted to it %d times in a row", event.ProjectV2Item.NodeID, depth)
+			break
+		}
+		loopGuard.ResetChain(event.ProjectV2Item.NodeID)
+
 		switch fieldType {
 		case "single_select":
-			nodeUpdated := projectDetails.FieldsByID[fieldNodeID].(lib.SingleSelectField)
+			if _, known := details.FieldsByID[fieldNodeID]; !known {
+				fmt.Printf("Unknown field %s, refreshing project details\n", fieldNodeID)
+				details = p.refresh()
+			}
+			nodeUpdated, ok := details.FieldsByID[fieldNodeID].(lib.SingleSelectField)
+			if !ok {
+				fmt.Printf("Unknown single select field %s\n", fieldNodeID)
+				break
+			}
 			fmt.Println("Field updated: ", nodeUpdated.Name)
 
-			switch nodeUpdated.Name {
-			case "Status":
+			if nodeUpdated.Name == p.config.StatusField {
 				if event.ProjectV2Item.NodeID == "" {
 					fmt.Println("No project item node ID")
 					break
 				}
-				itemDetails, err := ghClient.FetchStatusAndStartDate(event.ProjectV2Item.NodeID)
-				if err != nil {
-					log.Println("Error here", err)
-					return
-				}
-
-				var toUpdate string
-				var value *githubv4.ProjectV2FieldValue
-
-				value = &githubv4.ProjectV2FieldValue{
-					Date: githubv4.NewDate(githubv4.Date{
-						Time: time.Now(),
-					}),
-				}
-
-				if itemDetails.Status == "In progress" && itemDetails.StartDate == "" {
-					toUpdate = "Start date"
-				}
-
-				if itemDetails.Status == "Done" && itemDetails.EndDate == "" {
-					toUpdate = "End date"
-				}
-
-				if toUpdate != "" {
-					fmt.Println("Updating " + toUpdate)
-					f := projectDetails.FieldsByName[toUpdate].(lib.SingleSelectField)
-					err = ghClient.UpdateProjectItem(
-						event.ProjectV2Item.ProjectNodeID,
-						event.ProjectV2Item.NodeID,
-						f.ID,
-						*value,
-					)
-					if err != nil {
-						log.Println(err)
-					}
+				transition := statusTransition(fieldChanged)
+				// The item was moved by hand, so closing its pull request
+				// should leave it where it is.
+				p.takePreviousStatus(event.ProjectV2Item.NodeID)
+				handleStatusChange(p, details, event.ProjectV2Item, transition)
+				loopGuard.Reacted(event.ProjectV2Item.NodeID, now, time.Now())
+				// Moving to another column changes the ranks in both.
+				if p.config.Ranking.Enabled() {
+					p.scheduleRerank(transition.From, transition.To)
 				}
 			}
 		}
@@ -258,16 +697,51 @@ func handleProjectV2Item(event EventPayload) {
 func main() {
 	fmt.Println()
 	fmt.Println("--- Starting the application ---")
-	ghClient = lib.NewGithubClient()
 	var err error
-	projectDetails, err = ghClient.ProjectDetails("syntasso", 4)
+	config, err = lib.LoadConfig(os.Getenv("CONFIG_FILE"))
 	if err != nil {
-		log.Fatal("error to query project details", err)
+		log.Fatal("error loading configuration: ", err)
+	}
+
+	if config.Classifier.Model != "" {
+		classifier, err = lib.LoadClassifier(config.Classifier.Model)
+		if err != nil {
+			log.Fatal("error loading classifier model: ", err)
+		}
+	}
+
+	branchConvention, err = lib.NewBranchConvention(config.IssueBranchPattern)
+	if err != nil {
+		log.Fatal("error loading issue branch pattern: ", err)
+	}
+
+	ghClient = lib.NewGithubClient()
+	loopGuard = lib.NewLoopGuard(config.LoopPrevention.EchoWindow.Duration)
+	ghClient.TrackWrites(loopGuard)
+	for _, projectConfig := range config.Projects {
+		details, err := ghClient.ProjectDetails(config.Organization, projectConfig.Number, config.IssueTypes)
+		if err != nil {
+			log.Fatal("error to query project details", err)
+		}
+		fmt.Printf("Project %d ID: %s\n", projectConfig.Number, details.ID)
+		calendar, err := lib.NewCalendar(projectConfig.Calendar)
+		if err != nil {
+			log.Fatal("error loading project calendar: ", err)
+		}
+		projects = append(projects, newProject(projectConfig, details, calendar))
+	}
+	if len(projects) == 0 {
+		log.Fatal("no projects configured")
+	}
+
+	if config.SchemaCheckInterval.Duration > 0 {
+		go refreshProjects(config.SchemaCheckInterval.Duration)
 	}
-	fmt.Println("Project ID: ", projectDetails.ID)
 
 	r := mux.NewRouter()
 	r.HandleFunc("/", IncomingRequestHandler).Methods("POST")
+	r.HandleFunc("/healthz", HealthHandler).Methods("GET")
+	r.HandleFunc("/report", ReportHandler).Methods("GET")
 
 	srv := &http.Server{
 		Addr:    ":8080",
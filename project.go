@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kirederik/ghproject/lib"
+)
+
+// project is a configured board. Its details are swapped atomically when the
+// schema is refreshed, so handlers take one snapshot and use it throughout.
+type project struct {
+	id       string
+	config   lib.ProjectConfig
+	calendar *lib.Calendar
+	details  atomic.Pointer[lib.ProjectDetails]
+	// members caches the board's items so content already on it is not
+	// added again.
+	members *lib.Membership
+
+	refreshMu sync.Mutex
+
+	// rankMu guards the columns waiting to be reranked and the timer that
+	// will rerank them; rerankMu keeps reranks of the project in sequence.
+	rankMu      sync.Mutex
+	rankColumns map[string]bool
+	rankTimer   *time.Timer
+	rerankMu    sync.Mutex
+
+	mu              sync.RWMutex
+	schemaProblems  []lib.SchemaProblem
+	unmatchedScopes map[string]int
+	// previousStatus is the status an item had before the automation first
+	// moved it, so a closed pull request can put it back.
+	previousStatus map[string]string
+	archive        *lib.ArchiveLog
+}
+
+func newProject(projectConfig lib.ProjectConfig, details *lib.ProjectDetails, calendar *lib.Calendar) *project {
+	p := &project{
+		id:       details.ID,
+		config:   projectConfig,
+		calendar: calendar,
+		members:  lib.NewMembership(),
+		archive:  lib.NewArchiveLog(),
+	}
+	p.details.Store(details)
+	p.validateSchema(details)
+	p.warmMembership()
+	return p
+}
+
+// warmMembership replaces the membership cache and the archived items with
+// a listing of the project.
+func (p *project) warmMembership() {
+	listedAt := time.Now()
+	items, archived, err := ghClient.FetchProjectItems(p.id)
+	if err != nil {
+		log.Printf("Failed to list project %d items: %v", p.config.Number, err)
+		return
+	}
+	p.members.Replace(items, listedAt)
+	p.archive.Replace(archived, listedAt)
+	fmt.Printf("Project %d has %d items and %d archived\n", p.config.Number, len(items), len(archived))
+}
+
+// addItem returns the item of an issue or pull request, adding it to the
+// project only when the cache does not know it.
+func (p *project) addItem(contentID string) (string, error) {
+	if itemID, ok := p.members.ItemID(contentID); ok {
+		p.members.RecordSaved()
+		return itemID, nil
+	}
+	p.members.BeginAdd(contentID)
+	itemID, err := ghClient.AddNodeToProject(p.id, contentID)
+	if err != nil {
+		p.members.CancelAdd(contentID)
+		return "", err
+	}
+	p.members.Add(contentID, itemID)
+	return itemID, nil
+}
+
+// findItem returns the item of an issue or pull request that is already on
+// the project, or an empty ID when it is not, without adding it.
+func (p *project) findItem(contentID string) (string, error) {
+	if itemID, ok := p.members.ItemID(contentID); ok {
+		return itemID, nil
+	}
+	return ghClient.FetchProjectItemID(p.id, contentID)
+}
+
+func (p *project) snapshot() *lib.ProjectDetails {
+	return p.details.Load()
+}
+
+// refresh fetches the project schema again, swaps it in and validates it. If
+// the fetch fails the current snapshot is kept and returned.
+func (p *project) refresh() *lib.ProjectDetails {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	details, err := ghClient.ProjectDetails(config.Organization, p.config.Number, config.IssueTypes)
+	if err != nil {
+		log.Printf("Failed to refresh project %d details: %v", p.config.Number, err)
+		return p.snapshot()
+	}
+	p.details.Store(details)
+	fmt.Printf("Refreshed project %d details\n", p.config.Number)
+	p.validateSchema(details)
+	return details
+}
+
+// validateSchema records and reports the fields and options of details that
+// do not match the project configuration.
+func (p *project) validateSchema(details *lib.ProjectDetails) {
+	problems := p.config.ValidateSchema(details)
+
+	p.mu.Lock()
+	p.schemaProblems = problems
+	p.mu.Unlock()
+
+	if len(problems) == 0 {
+		fmt.Printf("Project %d schema matches the configuration\n", p.config.Number)
+		return
+	}
+	log.Printf("Project %d schema does not match the configuration:", p.config.Number)
+	for _, problem := range problems {
+		log.Printf("  - %s", problem)
+	}
+}
+
+func (p *project) problems() []lib.SchemaProblem {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.schemaProblems
+}
+
+// recordUnmatchedScope counts a title scope that selected no area option so
+// it shows up in the report.
+func (p *project) recordUnmatchedScope(scope string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.unmatchedScopes == nil {
+		p.unmatchedScopes = make(map[string]int)
+	}
+	p.unmatchedScopes[scope]++
+}
+
+func (p *project) recordPreviousStatus(itemID, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.previousStatus == nil {
+		p.previousStatus = make(map[string]string)
+	}
+	if _, ok := p.previousStatus[itemID]; !ok {
+		p.previousStatus[itemID] = status
+	}
+}
+
+// takePreviousStatus returns the status the item had before the automation
+// first moved it, which is empty when it had none, and whether the automation
+// moved it at all.
+func (p *project) takePreviousStatus(itemID string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status, ok := p.previousStatus[itemID]
+	delete(p.previousStatus, itemID)
+	return status, ok
+}
+
+type projectReport struct {
+	SchemaProblems  []lib.SchemaProblem `json:"schemaProblems,omitempty"`
+	UnmatchedScopes map[string]int      `json:"unmatchedScopes,omitempty"`
+	Items           int                 `json:"items"`
+	MutationsSaved  int                 `json:"mutationsSaved"`
+	ArchivedItems   int                 `json:"archivedItems"`
+	// ArchiveEvents and RestoreEvents count the events received since the
+	// process started, not the board's history.
+	ArchiveEvents int `json:"archiveEventsSinceStart"`
+	RestoreEvents int `json:"restoreEventsSinceStart"`
+}
+
+func (p *project) report() projectReport {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	archived, restored := p.archive.Events()
+	return projectReport{
+		SchemaProblems:  p.schemaProblems,
+		UnmatchedScopes: maps.Clone(p.unmatchedScopes),
+		Items:           p.members.Len(),
+		MutationsSaved:  p.members.Saved(),
+		ArchivedItems:   p.archive.Len(),
+		ArchiveEvents:   archived,
+		RestoreEvents:   restored,
+	}
+}
+
+func projectByNodeID(nodeID string) *project {
+	for _, p := range projects {
+		if p.id == nodeID {
+			return p
+		}
+	}
+	return nil
+}
+
+// currentTypeMapping returns the issue type mapping. Issue types belong to
+// the organization, so every project loads the same one.
+func currentTypeMapping() *lib.TypeMapping {
+	return projects[0].snapshot().TypeMapping
+}
+
+// refreshProjects fetches every project schema again on each tick, so fields
+// added on the board become usable and renamed or deleted ones are reported
+// before an event needs them. The membership cache and archived items are
+// rebuilt too, in case an event was missed.
+func refreshProjects(interval time.Duration) {
+	for range time.Tick(interval) {
+		for _, p := range projects {
+			p.refresh()
+			p.warmMembership()
+		}
+	}
+}
+
+func handleProjectV2(event EventPayload) {
+	p := projectByNodeID(event.ProjectV2.NodeID)
+	if p == nil {
+		fmt.Printf("Ignoring event from unknown project %s\n", event.ProjectV2.NodeID)
+		return
+	}
+	fmt.Printf("Project %d %s, refreshing details\n", p.config.Number, event.Action)
+	p.refresh()
+}
+
+// ReportHandler lists what the automation could not do on its own, per
+// project number.
+func ReportHandler(w http.ResponseWriter, r *http.Request) {
+	reports := make(map[int]projectReport)
+	for _, p := range projects {
+		reports[p.config.Number] = p.report()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	drift := make(map[int][]lib.SchemaProblem)
+	for _, p := range projects {
+		if problems := p.problems(); len(problems) > 0 {
+			drift[p.config.Number] = problems
+		}
+	}
+
+	if len(drift) == 0 {
+		w.Write([]byte("OK"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":         "schema drift",
+		"schemaProblems": drift,
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/kirederik/ghproject/lib"
+	"github.com/shurcooL/githubv4"
+)
+
+// rerankDelay is how long reranking waits for more reorders and moves on the
+// same project, so a burst of them costs one listing of the board.
+const rerankDelay = 5 * time.Second
+
+// scheduleRerank queues the status columns for reranking after rerankDelay,
+// together with any columns already queued for the project.
+func (p *project) scheduleRerank(columns ...string) {
+	p.rankMu.Lock()
+	defer p.rankMu.Unlock()
+	if p.rankColumns == nil {
+		p.rankColumns = make(map[string]bool)
+	}
+	for _, column := range columns {
+		p.rankColumns[column] = true
+	}
+	if p.rankTimer == nil {
+		p.rankTimer = time.AfterFunc(rerankDelay, p.runRerank)
+	}
+}
+
+// runRerank reranks the queued columns. Runs for one project never overlap.
+func (p *project) runRerank() {
+	p.rerankMu.Lock()
+	defer p.rerankMu.Unlock()
+
+	p.rankMu.Lock()
+	columns := slices.Collect(maps.Keys(p.rankColumns))
+	p.rankColumns = nil
+	p.rankTimer = nil
+	p.rankMu.Unlock()
+
+	rerankItems(p, p.snapshot(), columns)
+}
+
+// rerankItems writes each item's position within its status column into the
+// rank and priority fields for the given columns, so the board order survives
+// exports and shows in table views.
+func rerankItems(p *project, details *lib.ProjectDetails, columns []string) {
+	items, err := ghClient.FetchOrderedItems(p.id)
+	if err != nil {
+		log.Printf("Failed to list project %d items in order: %v", p.config.Number, err)
+		return
+	}
+
+	batcher := ghClient.NewMutationBatcher(lib.DefaultBatchSize)
+	for _, update := range p.config.Ranking.RankUpdates(p.config.StatusField, items, columns) {
+		fieldID, value, ok := rankFieldValue(details, update)
+		if !ok {
+			continue
+		}
+		batcher.Add(lib.UpdateFieldOperation{
+			ProjectID: p.id,
+			ItemID:    update.ItemID,
+			FieldID:   fieldID,
+			Value:     value,
+		})
+	}
+	if batcher.Len() == 0 {
+		return
+	}
+	fmt.Printf("Updating %d rank fields in project %d columns %q\n", batcher.Len(), p.config.Number, columns)
+	if _, err := batcher.Flush(); err != nil {
+		log.Println(err)
+	}
+}
+
+func rankFieldValue(details *lib.ProjectDetails, update lib.RankUpdate) (string, githubv4.ProjectV2FieldValue, bool) {
+	switch field := details.FieldsByName[update.Field].(type) {
+	case lib.SingleSelectField:
+		option, ok := field.OptionByName(update.Value)
+		if !ok {
+			log.Printf("Field %q has no %q option", update.Field, update.Value)
+			return "", githubv4.ProjectV2FieldValue{}, false
+		}
+		return field.ID, githubv4.ProjectV2FieldValue{
+			SingleSelectOptionID: githubv4.NewString(githubv4.String(option.ID)),
+		}, true
+	case lib.Field:
+		rank, err := strconv.Atoi(update.Value)
+		if err != nil {
+			log.Printf("Invalid rank %q for field %q", update.Value, update.Field)
+			return "", githubv4.ProjectV2FieldValue{}, false
+		}
+		return field.ID, githubv4.ProjectV2FieldValue{
+			Number: githubv4.NewFloat(githubv4.Float(rank)),
+		}, true
+	}
+	log.Printf("Field %q not found in project", update.Field)
+	return "", githubv4.ProjectV2FieldValue{}, false
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// handleBranchCreated starts the issue a new branch is named after, such as
+// 123 for "123-fix-login" under the default branch convention.
+func handleBranchCreated(event EventPayload) {
+	if event.Repository == nil {
+		return
+	}
+	number, ok := branchConvention.IssueNumber(event.Ref)
+	if !ok {
+		fmt.Printf("Branch %s in %s does not name an issue\n", event.Ref, event.Repository.FullName)
+		return
+	}
+	fmt.Printf("Branch %s in %s was created for issue #%d\n", event.Ref, event.Repository.FullName, number)
+	startIssues(event.Repository.FullName, []int{number})
+}
+
+// startIssues moves the given issues of repository to the Started status of
+// every project, unless they are already further along.
+func startIssues(repository string, numbers []int) {
+	if len(numbers) == 0 {
+		return
+	}
+	owner, name, ok := strings.Cut(repository, "/")
+	if !ok {
+		log.Printf("Unexpected repository name %q", repository)
+		return
+	}
+
+	for _, number := range numbers {
+		issueID, err := ghClient.FetchIssueID(owner, name, number)
+		if err != nil {
+			// References to pull requests and deleted issues end up here.
+			log.Printf("Failed to find issue %s#%d: %v", repository, number, err)
+			continue
+		}
+		for _, p := range projects {
+			// Only the issue number is known here, so a filtered project
+			// only moves issues that are already on it.
+			if _, onBoard := p.members.ItemID(issueID); !onBoard && !p.config.Filter.IsEmpty() {
+				continue
+			}
+			if status := p.config.PullRequestStatuses.Started; status != "" {
+				moveContentToStatus(p, issueID, status, true)
+			}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/kirederik/ghproject/lib"
+	"github.com/shurcooL/githubv4"
+)
+
+// statusTransition reads the previous and new option names from a
+// single_select field_value change.
+func statusTransition(fieldChanged ChangesetItem) lib.StatusTransition {
+	optionName := func(key string) string {
+		option, ok := fieldChanged[key].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		name, _ := option["name"].(string)
+		return name
+	}
+	return lib.StatusTransition{
+		From: optionName("from"),
+		To:   optionName("to"),
+	}
+}
+
+func handleStatusChange(p *project, details *lib.ProjectDetails, item *ProjectV2Item, transition lib.StatusTransition) {
+	itemDetails, err := ghClient.FetchProjectItem(item.NodeID)
+	if err != nil {
+		log.Println("Error here", err)
+		return
+	}
+
+	if p.config.IsBackward(transition) {
+		fmt.Printf("Item moved backwards from %q to %q\n", transition.From, transition.To)
+		for _, fieldName := range p.config.FieldsToClear(transition) {
+			if itemDetails.Values[fieldName] == "" {
+				continue
+			}
+			fieldID, ok := details.FieldID(fieldName)
+			if !ok {
+				log.Printf("Field %q not found in project, cannot clear it", fieldName)
+				continue
+			}
+			fmt.Println("Clearing " + fieldName)
+			if err := ghClient.ClearProjectItemField(item.ProjectNodeID, item.NodeID, fieldID); err != nil {
+				log.Println(err)
+				continue
+			}
+			delete(itemDetails.Values, fieldName)
+		}
+
+		if p.config.IsReopen(transition) && p.config.Transitions.ReopenCountField != "" {
+			countReopening(p, details, item, itemDetails)
+		}
+	}
+
+	today := p.calendar.Today(time.Now())
+	value := githubv4.ProjectV2FieldValue{
+		Date: githubv4.NewDate(githubv4.Date{
+			Time: today,
+		}),
+	}
+
+	batcher := ghClient.NewMutationBatcher(lib.DefaultBatchSize)
+	for _, toUpdate := range p.config.DateFieldsToStamp(itemDetails.Values[p.config.StatusField], itemDetails.Values) {
+		if itemDetails.Values[toUpdate] == lib.FormatDate(today) {
+			continue
+		}
+		fieldID, ok := details.FieldID(toUpdate)
+		if !ok {
+			log.Printf("Field %q not found in project", toUpdate)
+			continue
+		}
+		fmt.Println("Updating " + toUpdate)
+		batcher.Add(lib.UpdateFieldOperation{
+			ProjectID: item.ProjectNodeID,
+			ItemID:    item.NodeID,
+			FieldID:   fieldID,
+			Value:     value,
+		})
+		itemDetails.Values[toUpdate] = lib.FormatDate(today)
+		addDurations(p, details, item, itemDetails, toUpdate, batcher)
+	}
+	if _, err := batcher.Flush(); err != nil {
+		log.Println(err)
+	}
+}
+
+// addDurations queues an update for every duration that ends with the date
+// field that was just stamped.
+func addDurations(p *project, details *lib.ProjectDetails, item *ProjectV2Item, itemDetails *lib.ProjectItem, stamped string, batcher *lib.MutationBatcher) {
+	for _, duration := range p.config.Durations {
+		if duration.End != stamped || itemDetails.Values[duration.Start] == "" {
+			continue
+		}
+		start, err := lib.ParseDate(itemDetails.Values[duration.Start])
+		if err != nil {
+			log.Printf("Field %q has invalid date: %v", duration.Start, err)
+			continue
+		}
+		end, err := lib.ParseDate(itemDetails.Values[duration.End])
+		if err != nil {
+			log.Printf("Field %q has invalid date: %v", duration.End, err)
+			continue
+		}
+		fieldID, ok := details.FieldID(duration.Field)
+		if !ok {
+			log.Printf("Field %q not found in project", duration.Field)
+			continue
+		}
+
+		days := p.calendar.WorkingDaysBetween(start, end)
+		if itemDetails.Values[duration.Field] == strconv.Itoa(days) {
+			continue
+		}
+		fmt.Printf("Updating %s to %d working days\n", duration.Field, days)
+		batcher.Add(lib.UpdateFieldOperation{
+			ProjectID: item.ProjectNodeID,
+			ItemID:    item.NodeID,
+			FieldID:   fieldID,
+			Value: githubv4.ProjectV2FieldValue{
+				Number: githubv4.NewFloat(githubv4.Float(days)),
+			},
+		})
+	}
+}
+
+func countReopening(p *project, details *lib.ProjectDetails, item *ProjectV2Item, itemDetails *lib.ProjectItem) {
+	fieldName := p.config.Transitions.ReopenCountField
+	fieldID, ok := details.FieldID(fieldName)
+	if !ok {
+		log.Printf("Field %q not found in project, cannot count reopenings", fieldName)
+		return
+	}
+
+	var count float64
+	if current := itemDetails.Values[fieldName]; current != "" {
+		parsed, err := strconv.ParseFloat(current, 64)
+		if err != nil {
+			log.Printf("Field %q has non-numeric value %q", fieldName, current)
+			return
+		}
+		count = parsed
+	}
+
+	fmt.Printf("Updating %s to %v\n", fieldName, count+1)
+	err := ghClient.UpdateProjectItem(item.ProjectNodeID, item.NodeID, fieldID, githubv4.ProjectV2FieldValue{
+		Number: githubv4.NewFloat(githubv4.Float(count + 1)),
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// moveItemToStatus sets the item's status and applies the same date and
+// transition rules as a move made on the board.
+func moveItemToStatus(p *project, itemID, status string, forwardOnly bool) error {
+	details := p.snapshot()
+	field, ok := details.FieldsByName[p.config.StatusField].(lib.SingleSelectField)
+	if !ok {
+		return fmt.Errorf("status field %q not found in project %d", p.config.StatusField, p.config.Number)
+	}
+	option, ok := field.OptionByName(status)
+	if !ok {
+		return fmt.Errorf("status field %q has no %q option in project %d", field.Name, status, p.config.Number)
+	}
+
+	started := time.Now()
+	if depth := loopGuard.ChainDepth(itemID, started); depth > config.LoopPrevention.MaxChainDepth {
+		return fmt.Errorf("the automation reacted to item %s %d times in a row", itemID, depth)
+	}
+	defer func() { loopGuard.Reacted(itemID, started, time.Now()) }()
+
+	itemDetails, err := ghClient.FetchProjectItem(itemID)
+	if err != nil {
+		return err
+	}
+	from := itemDetails.Values[p.config.StatusField]
+	settled := status == p.config.PullRequestStatuses.Merged
+	if settled {
+		// A merge is final, so there is no earlier status to go back to.
+		p.takePreviousStatus(itemID)
+	}
+	if from == status {
+		return nil
+	}
+	if forwardOnly && p.config.IsBackward(lib.StatusTransition{From: from, To: status}) {
+		fmt.Printf("Leaving item %s in %q rather than moving it back to %q\n", itemID, from, status)
+		return nil
+	}
+
+	if err := setSingleSelect(p.id, itemID, field, option); err != nil {
+		return err
+	}
+	if !settled {
+		p.recordPreviousStatus(itemID, from)
+	}
+	fmt.Printf("Moved item %s from %q to %q\n", itemID, from, status)
+
+	item := &ProjectV2Item{ProjectNodeID: p.id, NodeID: itemID}
+	handleStatusChange(p, details, item, lib.StatusTransition{From: from, To: status})
+	if p.config.Ranking.Enabled() {
+		p.scheduleRerank(from, status)
+	}
+	return nil
+}
+
+// moveContentToStatus moves the project item of an issue or pull request,
+// adding it to the project first if needed. With forwardOnly set, items that
+// are already further along the board stay where they are.
+func moveContentToStatus(p *project, contentID, status string, forwardOnly bool) {
+	itemID, err := p.addItem(contentID)
+	if err != nil {
+		log.Printf("Failed to find %s in project %d: %v", contentID, p.config.Number, err)
+		return
+	}
+	if err := moveItemToStatus(p, itemID, status, forwardOnly); err != nil {
+		log.Printf("Failed to move %s to %q: %v", contentID, status, err)
+	}
+}